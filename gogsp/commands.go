@@ -0,0 +1,417 @@
+package main
+
+import (
+	"errors"
+	"example/gogsp/core"
+	"example/gogsp/filters"
+	"example/gogsp/graphs"
+	"example/gogsp/plot"
+	"example/gogsp/signals"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// graphFlags are the flags that control how a graph file is read.
+type graphFlags struct {
+	format    string
+	laplacian string
+	directed  bool
+}
+
+func (o *graphFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "graph-format", "", "graph file `format`: edgelist, csv, adjacency, mtx, graphml or gml (default from the extension)")
+	fs.StringVar(&o.laplacian, "laplacian", "combinatorial", "Laplacian: combinatorial, normalized, random-walk or signless")
+	fs.BoolVar(&o.directed, "directed", false, "read the graph as directed")
+}
+
+// signalFlags are the flags that control how signal files are read and written.
+type signalFlags struct {
+	format string
+	array  string
+	output string
+	to     string
+}
+
+func (o *signalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "signal-format", "", "signal file `format`: csv, json, npy or npz (default from the extension)")
+	fs.StringVar(&o.array, "array", "", "array to read from a .npz file, if it holds several")
+	fs.StringVar(&o.output, "o", "-", "output `file`, - for standard output")
+	fs.StringVar(&o.to, "to", "", "output `format` (default from the extension of -o, csv on standard output)")
+}
+
+// parse parses the flags of a command, before or after its positional arguments, and checks the number of positional
+// arguments, which are then fs.Args().
+func parse(fs *flag.FlagSet, args []string, min, max int) error {
+	var positional []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+			return errUsage
+		}
+		// The flag package stops at the first positional argument, or after a "--" that ends the flags
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	// Parsing the positional arguments alone leaves them as the arguments of the flag set
+	_ = fs.Parse(append([]string{"--"}, positional...))
+	if n := fs.NArg(); n < min || n > max {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// newFlagSet returns the flag set of a command, with a usage line listing its arguments.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, c := range commands {
+			if c.name == name {
+				fmt.Fprintf(fs.Output(), "Usage: gogsp %s [flags] %s\n\n%s.\n\nFlags:\n", name, c.args, c.summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func parseMethod(name string) (filters.Method, error) {
+	switch name {
+	case "exact":
+		return filters.MethodExact, nil
+	case "chebyshev":
+		return filters.MethodChebyshev, nil
+	case "lanczos":
+		return filters.MethodLanczos, nil
+	}
+	return 0, fmt.Errorf("unknown method %q, expected exact, chebyshev or lanczos", name)
+}
+
+func runInfo(args []string) error {
+	fs := newFlagSet("info")
+	var gf graphFlags
+	gf.register(fs)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	g, err := loadGraph(fs.Arg(0), &gf)
+	if err != nil {
+		return err
+	}
+
+	nodes := g.Nodes()
+	edges, selfLoops := 0, 0
+	total := 0.0
+	degrees := make([]int, len(nodes))
+	for i, n := range nodes {
+		for _, edge := range g.AdjacencyList[n] {
+			degrees[i]++
+			if edge.Node == n {
+				selfLoops++
+			}
+			if g.IsDirected() || edge.Node >= n {
+				edges++
+				total += float64(edge.Weight)
+			}
+		}
+	}
+	row := func(name string, format string, args ...interface{}) {
+		fmt.Printf("%-17s "+format+"\n", append([]interface{}{name}, args...)...)
+	}
+	row("nodes", "%d", len(nodes))
+	row("edges", "%d", edges)
+	row("self-loops", "%d", selfLoops)
+	row("total weight", "%g", total)
+	row("directed", "%t", g.IsDirected())
+	row("laplacian", "%v", g.LaplacianKind())
+	if len(nodes) > 0 {
+		sorted := append([]int(nil), degrees...)
+		sort.Ints(sorted)
+		sum := 0
+		for _, d := range sorted {
+			sum += d
+		}
+		row("degree", "min %d, mean %.4g, max %d", sorted[0], float64(sum)/float64(len(sorted)), sorted[len(sorted)-1])
+	}
+
+	_, weak := g.ConnectedComponents()
+	if g.IsDirected() {
+		_, strong := g.StronglyConnectedComponents()
+		row("components", "%d weakly, %d strongly connected", weak, strong)
+	} else {
+		row("components", "%d", weak)
+	}
+	row("connected", "%t", g.IsFullyConnected())
+
+	if err := g.CheckSymmetric(); err != nil {
+		row("spectrum", "not estimated, %v", err)
+	} else if len(nodes) > 0 {
+		estimate, err := g.EstimateSpectrum(core.LanczosOptions{})
+		switch {
+		case err != nil:
+			row("spectrum", "not estimated, %v", err)
+		case estimate.Converged:
+			row("spectrum", "[%.6g, %.6g]", estimate.LMin, estimate.LMax)
+		default:
+			row("spectrum", "[%.6g, %.6g], not converged after %d iterations", estimate.LMin, estimate.LMax, estimate.Iterations)
+		}
+	}
+
+	if d := g.CoordinateDims(); d > 0 {
+		row("coordinates", "%d dimensions", d)
+	}
+	attributes := func(keys []string, kind func(string) (core.AttributeKind, bool)) string {
+		described := make([]string, len(keys))
+		for i, key := range keys {
+			k, _ := kind(key)
+			described[i] = fmt.Sprintf("%s (%v)", key, k)
+		}
+		return strings.Join(described, ", ")
+	}
+	if keys := g.NodeAttributeKeys(); len(keys) > 0 {
+		row("node attributes", "%s", attributes(keys, g.NodeAttributeKind))
+	}
+	if keys := g.EdgeAttributeKeys(); len(keys) > 0 {
+		row("edge attributes", "%s", attributes(keys, g.EdgeAttributeKind))
+	}
+	return nil
+}
+
+func runConvert(args []string) error {
+	fs := newFlagSet("convert")
+	var gf graphFlags
+	gf.register(fs)
+	to := fs.String("to", "", "output `format`: edgelist, csv, adjacency, mtx, graphml, gml or dot (default from the extension)")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	g, err := loadGraph(fs.Arg(0), &gf)
+	if err != nil {
+		return err
+	}
+	return saveGraph(fs.Arg(1), *to, g)
+}
+
+// mapColumns applies fn to every column of m, the signals of a file.
+func mapColumns(m *mat.Dense, fn func(signals.Signal) (signals.Signal, error)) (*mat.Dense, error) {
+	r, c := m.Dims()
+	out := mat.NewDense(r, c, nil)
+	for t := 0; t < c; t++ {
+		s, err := fn(signals.Signal(mat.Col(nil, t, m)))
+		if err != nil {
+			return nil, err
+		}
+		out.SetCol(t, s)
+	}
+	return out, nil
+}
+
+// runTransform runs gft or igft. Coefficients are stored like signals, coefficient k in the row of the k-th node
+// in matrix order.
+func runTransform(name string, args []string, inverse bool) error {
+	fs := newFlagSet(name)
+	var gf graphFlags
+	var sf signalFlags
+	gf.register(fs)
+	sf.register(fs)
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	g, err := loadGraph(fs.Arg(0), &gf)
+	if err != nil {
+		return err
+	}
+	m, err := loadSignals(fs.Arg(1), &sf, g)
+	if err != nil {
+		return err
+	}
+	basis, err := g.Basis()
+	if err != nil {
+		return err
+	}
+	transform, array := basis.GraphFourierTransform, "coefficients"
+	if inverse {
+		transform, array = basis.InverseGraphFourierTransform, "signal"
+	}
+	out, err := mapColumns(m, transform)
+	if err != nil {
+		return err
+	}
+	return saveSignals(sf.output, sf.to, g, out, array)
+}
+
+func runGFT(args []string) error {
+	return runTransform("gft", args, false)
+}
+
+func runIGFT(args []string) error {
+	return runTransform("igft", args, true)
+}
+
+func runFilter(args []string) error {
+	fs := newFlagSet("filter")
+	var gf graphFlags
+	var sf signalFlags
+	gf.register(fs)
+	sf.register(fs)
+	kernel := fs.String("kernel", "heat", "kernel: heat, lowpass or highpass")
+	tau := fs.Float64("tau", 10, "scale of the heat kernel exp(-tau λ / lmax)")
+	cutoff := fs.Float64("cutoff", 0.5, "cutoff of the low- and high-pass kernels, as a fraction of lmax")
+	response := fs.String("response", "butterworth", "response of the low- and high-pass kernels: ideal, butterworth or heat")
+	order := fs.Int("order", 2, "order of the butterworth response")
+	methodName := fs.String("method", "exact", "method: exact, chebyshev or lanczos")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	method, err := parseMethod(*methodName)
+	if err != nil {
+		return err
+	}
+	g, err := loadGraph(fs.Arg(0), &gf)
+	if err != nil {
+		return err
+	}
+	m, err := loadSignals(fs.Arg(1), &sf, g)
+	if err != nil {
+		return err
+	}
+
+	var filter *filters.Filter
+	switch *kernel {
+	case "heat":
+		heat, err := filters.NewHeat(g, method, []float64{*tau}, false)
+		if err != nil {
+			return err
+		}
+		filter = heat.Filter
+	case "lowpass", "highpass":
+		var r filters.Response
+		switch *response {
+		case "ideal":
+			r = filters.Ideal()
+		case "butterworth":
+			r = filters.Butterworth(*order)
+		case "heat":
+			r = filters.HeatDecay()
+		default:
+			return fmt.Errorf("unknown response %q, expected ideal, butterworth or heat", *response)
+		}
+		build := filters.NewLowPass
+		if *kernel == "highpass" {
+			build = filters.NewHighPass
+		}
+		if filter, err = build(g, method, r, filters.FractionOfLMax(*cutoff)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown kernel %q, expected heat, lowpass or highpass", *kernel)
+	}
+
+	out, err := mapColumns(m, filter.Apply)
+	if err != nil {
+		return err
+	}
+	return saveSignals(sf.output, sf.to, g, out, "signal")
+}
+
+func runWavelet(args []string) error {
+	fs := newFlagSet("wavelet")
+	var gf graphFlags
+	var sf signalFlags
+	gf.register(fs)
+	sf.register(fs)
+	scales := fs.Int("scales", 4, "number of wavelet scales J; the output has J+1 columns, the scaling function first")
+	methodName := fs.String("method", "exact", "method: exact, chebyshev or lanczos")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	method, err := parseMethod(*methodName)
+	if err != nil {
+		return err
+	}
+	g, err := loadGraph(fs.Arg(0), &gf)
+	if err != nil {
+		return err
+	}
+	m, err := loadSignals(fs.Arg(1), &sf, g)
+	if err != nil {
+		return err
+	}
+	if _, c := m.Dims(); c != 1 {
+		return fmt.Errorf("the wavelet transform takes a single signal, the file holds %d", c)
+	}
+	sgwt, err := filters.NewSGWT(g, *scales, method)
+	if err != nil {
+		return err
+	}
+	coefficients, err := sgwt.Analyze(signals.Signal(mat.Col(nil, 0, m)))
+	if err != nil {
+		return err
+	}
+	return saveSignals(sf.output, sf.to, g, coefficients, "coefficients")
+}
+
+func runPlot(args []string) error {
+	fs := newFlagSet("plot")
+	var gf graphFlags
+	var sf signalFlags
+	gf.register(fs)
+	fs.StringVar(&sf.format, "signal-format", "", "signal file `format`: csv, json, npy or npz (default from the extension)")
+	fs.StringVar(&sf.array, "array", "", "array to read from a .npz file, if it holds several")
+	output := fs.String("o", "graph.png", "output `file`, .png or .dot")
+	column := fs.Int("column", 0, "signal to plot when the file holds several")
+	if err := parse(fs, args, 1, 2); err != nil {
+		return err
+	}
+	g, err := loadGraph(fs.Arg(0), &gf)
+	if err != nil {
+		return err
+	}
+	var signal signals.Signal
+	if fs.NArg() == 2 {
+		m, err := loadSignals(fs.Arg(1), &sf, g)
+		if err != nil {
+			return err
+		}
+		if _, c := m.Dims(); *column < 0 || *column >= c {
+			return fmt.Errorf("column %d out of range for a file of %d signals", *column, c)
+		}
+		signal = mat.Col(nil, *column, m)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(*output)); ext {
+	case ".png":
+		// The plot functions add the extension themselves
+		name := strings.TrimSuffix(*output, filepath.Ext(*output))
+		if signal != nil {
+			return plot.PlotGraphSignal(g, signal, name)
+		}
+		return plot.PlotGraph(g, name)
+	case ".dot", ".gv":
+		var opts []graphs.DOTOption
+		if signal != nil {
+			opts = append(opts, graphs.ColorBy(signal))
+		}
+		return create(*output, func(w io.Writer) error {
+			return graphs.WriteDOT(w, g, opts...)
+		})
+	default:
+		return fmt.Errorf("cannot plot to %q, expected a .png or .dot file", *output)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"example/gogsp/graphs"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		args       []string
+		positional []string
+		to         string
+		directed   bool
+		err        error
+	}{
+		{[]string{"a.txt", "b.gml"}, []string{"a.txt", "b.gml"}, "", false, nil},
+		{[]string{"-to", "gml", "a.txt", "b"}, []string{"a.txt", "b"}, "gml", false, nil},
+		{[]string{"a.txt", "-directed", "b", "-to=mtx"}, []string{"a.txt", "b"}, "mtx", true, nil},
+		{[]string{"a.txt", "--", "-b"}, []string{"a.txt", "-b"}, "", false, nil},
+		{[]string{"a.txt"}, nil, "", false, errUsage},
+		{[]string{"a.txt", "b", "c"}, nil, "", false, errUsage},
+		{[]string{"-unknown", "a.txt", "b"}, nil, "", false, errUsage},
+		{[]string{"-h"}, nil, "", false, flag.ErrHelp},
+	}
+	for _, test := range tests {
+		fs := newFlagSet("convert")
+		fs.SetOutput(io.Discard)
+		var gf graphFlags
+		gf.register(fs)
+		to := fs.String("to", "", "")
+		err := parse(fs, test.args, 2, 2)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: got error %v, want %v", test.args, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if strings.Join(fs.Args(), " ") != strings.Join(test.positional, " ") || *to != test.to || gf.directed != test.directed {
+			t.Errorf("%q: got arguments %q, -to %q and -directed %t, want %q, %q and %t",
+				test.args, fs.Args(), *to, gf.directed, test.positional, test.to, test.directed)
+		}
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "graph.txt")
+	const list = "a b 0.5\na c 1.25\nb c 2\nd\n"
+	if err := os.WriteFile(input, []byte(list), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Through every format that can be read back, then back to an edge list
+	previous := input
+	for _, ext := range []string{".graphml", ".gml", ".mtx", ".csv"} {
+		output := filepath.Join(dir, "graph"+ext)
+		if err := runConvert([]string{previous, output}); err != nil {
+			t.Fatalf("%s: %v", ext, err)
+		}
+		previous = output
+	}
+	final := filepath.Join(dir, "final.edges")
+	if err := runConvert([]string{"-to", "edgelist", previous, final}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(final)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != list {
+		t.Errorf("got\n%s\nwant\n%s", got, list)
+	}
+
+	// The converted graph is the same graph
+	g, err := loadGraph(final, &graphFlags{laplacian: "combinatorial"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := g.NodeByLabel("a")
+	c, _ := g.NodeByLabel("c")
+	if len(g.Nodes()) != 4 || !g.HasEdge(c, a) || g.AdjacencyList[a][0].Weight != graphs.Weight(0.5) {
+		t.Errorf("got the nodes %v and edges %v", g.Nodes(), g.AdjacencyList)
+	}
+
+	if err := runConvert([]string{input, filepath.Join(dir, "graph.unknown")}); err == nil {
+		t.Error("an unknown extension is accepted")
+	}
+}
@@ -0,0 +1,292 @@
+// attributes.go contains the typed attributes of nodes and edges, e.g. a sensor name or a road type.
+// Each attribute key has a single kind per graph, the first value stored under it decides which, so that file
+// formats with typed columns, such as GraphML, can declare them. Attributes are stored by node ID, so they are
+// unaffected by reindexing, and they are dropped together with their node or edge.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// AttributeKind is the type of an attribute value.
+type AttributeKind int
+
+const (
+	FloatAttribute AttributeKind = iota
+	IntAttribute
+	StringAttribute
+	BoolAttribute
+)
+
+func (k AttributeKind) String() string {
+	switch k {
+	case FloatAttribute:
+		return "float"
+	case IntAttribute:
+		return "int"
+	case StringAttribute:
+		return "string"
+	case BoolAttribute:
+		return "bool"
+	}
+	return fmt.Sprintf("AttributeKind(%d)", int(k))
+}
+
+// ParseAttributeKind returns the kind with the given name, as printed by String.
+func ParseAttributeKind(name string) (AttributeKind, error) {
+	for _, k := range []AttributeKind{FloatAttribute, IntAttribute, StringAttribute, BoolAttribute} {
+		if k.String() == name {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown attribute kind %q", name)
+}
+
+// Value is an attribute value of one of the attribute kinds.
+type Value struct {
+	kind AttributeKind
+	f    float64
+	i    int64
+	s    string
+	b    bool
+}
+
+func FloatValue(f float64) Value {
+	return Value{kind: FloatAttribute, f: f}
+}
+
+func IntValue(i int64) Value {
+	return Value{kind: IntAttribute, i: i}
+}
+
+func StringValue(s string) Value {
+	return Value{kind: StringAttribute, s: s}
+}
+
+func BoolValue(b bool) Value {
+	return Value{kind: BoolAttribute, b: b}
+}
+
+// ParseValue reads a value of the given kind from its String form.
+func ParseValue(kind AttributeKind, text string) (Value, error) {
+	switch kind {
+	case FloatAttribute:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return Value{}, err
+		}
+		return FloatValue(f), nil
+	case IntAttribute:
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return Value{}, err
+		}
+		return IntValue(i), nil
+	case StringAttribute:
+		return StringValue(text), nil
+	case BoolAttribute:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return Value{}, err
+		}
+		return BoolValue(b), nil
+	}
+	return Value{}, fmt.Errorf("unknown attribute kind %d", int(kind))
+}
+
+// Kind returns the kind of the value.
+func (v Value) Kind() AttributeKind {
+	return v.kind
+}
+
+// Float returns a float or int value as a float64, and reports whether the value is numeric.
+func (v Value) Float() (float64, bool) {
+	switch v.kind {
+	case FloatAttribute:
+		return v.f, true
+	case IntAttribute:
+		return float64(v.i), true
+	}
+	return 0, false
+}
+
+// Int returns an int value.
+func (v Value) Int() (int64, bool) {
+	return v.i, v.kind == IntAttribute
+}
+
+// Text returns a string value.
+func (v Value) Text() (string, bool) {
+	return v.s, v.kind == StringAttribute
+}
+
+// Bool returns a bool value.
+func (v Value) Bool() (bool, bool) {
+	return v.b, v.kind == BoolAttribute
+}
+
+// String formats the value so that ParseValue reads it back.
+func (v Value) String() string {
+	switch v.kind {
+	case FloatAttribute:
+		return strconv.FormatFloat(v.f, 'g', -1, 64)
+	case IntAttribute:
+		return strconv.FormatInt(v.i, 10)
+	case BoolAttribute:
+		return strconv.FormatBool(v.b)
+	}
+	return v.s
+}
+
+// arc identifies an edge for its attributes. Undirected edges are stored with the smaller node first.
+type arc struct {
+	from, to Node
+}
+
+func (g *Graph) arcOf(n1, n2 Node) arc {
+	if !g.directed && n2 < n1 {
+		n1, n2 = n2, n1
+	}
+	return arc{n1, n2}
+}
+
+// attributeTable holds the attributes of either nodes or edges, and the kind of every key.
+type attributeTable[K comparable] struct {
+	kinds  map[string]AttributeKind
+	values map[K]map[string]Value
+}
+
+func (t *attributeTable[K]) set(k K, key string, v Value) error {
+	if kind, ok := t.kinds[key]; ok && kind != v.kind {
+		return fmt.Errorf("attribute %q holds %v values, got a %v", key, kind, v.kind)
+	}
+	if t.kinds == nil {
+		t.kinds = make(map[string]AttributeKind)
+		t.values = make(map[K]map[string]Value)
+	}
+	t.kinds[key] = v.kind
+	if t.values[k] == nil {
+		t.values[k] = make(map[string]Value)
+	}
+	t.values[k][key] = v
+	return nil
+}
+
+func (t *attributeTable[K]) get(k K, key string) (Value, bool) {
+	v, ok := t.values[k][key]
+	return v, ok
+}
+
+func (t *attributeTable[K]) all(k K) map[string]Value {
+	values := make(map[string]Value, len(t.values[k]))
+	for key, v := range t.values[k] {
+		values[key] = v
+	}
+	return values
+}
+
+func (t *attributeTable[K]) delete(k K, key string) {
+	delete(t.values[k], key)
+	if len(t.values[k]) == 0 {
+		delete(t.values, k)
+	}
+}
+
+func (t *attributeTable[K]) keys() []string {
+	keys := make([]string, 0, len(t.kinds))
+	for key := range t.kinds {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (t *attributeTable[K]) kind(key string) (AttributeKind, bool) {
+	kind, ok := t.kinds[key]
+	return kind, ok
+}
+
+// SetNodeAttribute stores an attribute of an existing node.
+func (g *Graph) SetNodeAttribute(n Node, key string, v Value) error {
+	if _, present := g.AdjacencyList[n]; !present {
+		return fmt.Errorf("node %v is not in the graph", n)
+	}
+	return g.nodeAttributes.set(n, key, v)
+}
+
+// NodeAttribute returns an attribute of the node.
+func (g *Graph) NodeAttribute(n Node, key string) (Value, bool) {
+	return g.nodeAttributes.get(n, key)
+}
+
+// NodeAttributes returns a copy of all attributes of the node.
+func (g *Graph) NodeAttributes(n Node) map[string]Value {
+	return g.nodeAttributes.all(n)
+}
+
+// DeleteNodeAttribute removes an attribute of the node, if it has it.
+func (g *Graph) DeleteNodeAttribute(n Node, key string) {
+	g.nodeAttributes.delete(n, key)
+}
+
+// NodeAttributeKeys returns every node attribute key ever set on the graph, sorted.
+func (g *Graph) NodeAttributeKeys() []string {
+	return g.nodeAttributes.keys()
+}
+
+// NodeAttributeKind returns the kind of the values stored under a node attribute key.
+func (g *Graph) NodeAttributeKind(key string) (AttributeKind, bool) {
+	return g.nodeAttributes.kind(key)
+}
+
+// SetEdgeAttribute stores an attribute of an existing edge. Undirected edges have a single set of attributes,
+// whichever direction they are given in.
+func (g *Graph) SetEdgeAttribute(n1, n2 Node, key string, v Value) error {
+	if !g.HasEdge(n1, n2) {
+		return fmt.Errorf("edge %v -> %v is not in the graph", n1, n2)
+	}
+	return g.edgeAttributes.set(g.arcOf(n1, n2), key, v)
+}
+
+// EdgeAttribute returns an attribute of the edge.
+func (g *Graph) EdgeAttribute(n1, n2 Node, key string) (Value, bool) {
+	return g.edgeAttributes.get(g.arcOf(n1, n2), key)
+}
+
+// EdgeAttributes returns a copy of all attributes of the edge.
+func (g *Graph) EdgeAttributes(n1, n2 Node) map[string]Value {
+	return g.edgeAttributes.all(g.arcOf(n1, n2))
+}
+
+// DeleteEdgeAttribute removes an attribute of the edge, if it has it.
+func (g *Graph) DeleteEdgeAttribute(n1, n2 Node, key string) {
+	g.edgeAttributes.delete(g.arcOf(n1, n2), key)
+}
+
+// EdgeAttributeKeys returns every edge attribute key ever set on the graph, sorted.
+func (g *Graph) EdgeAttributeKeys() []string {
+	return g.edgeAttributes.keys()
+}
+
+// EdgeAttributeKind returns the kind of the values stored under an edge attribute key.
+func (g *Graph) EdgeAttributeKind(key string) (AttributeKind, bool) {
+	return g.edgeAttributes.kind(key)
+}
+
+// dropEdgeAttributes forgets the attributes of a removed edge.
+func (g *Graph) dropEdgeAttributes(n1, n2 Node) {
+	delete(g.edgeAttributes.values, g.arcOf(n1, n2))
+}
+
+// dropNodeAttributes forgets the attributes of a removed node and of all its edges.
+func (g *Graph) dropNodeAttributes(n Node) {
+	delete(g.nodeAttributes.values, n)
+	for a := range g.edgeAttributes.values {
+		if a.from == n || a.to == n {
+			delete(g.edgeAttributes.values, a)
+		}
+	}
+}
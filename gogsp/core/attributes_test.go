@@ -0,0 +1,107 @@
+package core
+
+import "testing"
+
+func TestNodeAttributes(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	values := map[string]Value{
+		"weight": FloatValue(0.5),
+		"count":  IntValue(-3),
+		"name":   StringValue("a b"),
+		"active": BoolValue(true),
+	}
+	for key, v := range values {
+		if err := g.SetNodeAttribute(1, key, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, want := range values {
+		v, ok := g.NodeAttribute(1, key)
+		if !ok || v.Kind() != want.Kind() || v != want {
+			t.Errorf("%s is %v (%v), want %v (%v)", key, v, v.Kind(), want, want.Kind())
+		}
+		if kind, _ := g.NodeAttributeKind(key); kind != want.Kind() {
+			t.Errorf("%s has kind %v, want %v", key, kind, want.Kind())
+		}
+		parsed, err := ParseValue(want.Kind(), want.String())
+		if err != nil || parsed != want {
+			t.Errorf("%s reads back as %v, %v", key, parsed, err)
+		}
+	}
+	if f, ok := values["count"].Float(); !ok || f != -3 {
+		t.Errorf("an int reads as the float %v, %t", f, ok)
+	}
+	if _, ok := values["name"].Int(); ok {
+		t.Error("a string reads as an int")
+	}
+
+	// A key keeps the kind of its first value
+	if err := g.SetNodeAttribute(2, "count", FloatValue(1)); err == nil {
+		t.Error("a float is stored under an int key")
+	}
+	if err := g.SetNodeAttribute(4, "count", IntValue(1)); err == nil {
+		t.Error("an attribute is stored on a missing node")
+	}
+
+	g.DeleteNodeAttribute(1, "name")
+	if _, ok := g.NodeAttribute(1, "name"); ok {
+		t.Error("the deleted attribute is still there")
+	}
+	if keys := g.NodeAttributeKeys(); len(keys) != 4 || keys[0] != "active" {
+		t.Errorf("got the keys %v", keys)
+	}
+
+	if _, ok := g.RemoveNode(1); !ok {
+		t.Fatal("node 1 is missing")
+	}
+	if len(g.NodeAttributes(1)) != 0 {
+		t.Errorf("the removed node keeps the attributes %v", g.NodeAttributes(1))
+	}
+	// Adding the node again does not bring its attributes back
+	g.AddNode(1)
+	if _, ok := g.NodeAttribute(1, "count"); ok {
+		t.Error("a new node inherits the attributes of a removed one")
+	}
+}
+
+func TestEdgeAttributes(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	if err := g.SetEdgeAttribute(2, 1, "kind", StringValue("road")); err != nil {
+		t.Fatal(err)
+	}
+	// Undirected edges have one set of attributes whichever way they are given
+	if v, ok := g.EdgeAttribute(1, 2, "kind"); !ok || v.String() != "road" {
+		t.Errorf("edge 1 - 2 has kind %v", v)
+	}
+	if err := g.SetEdgeAttribute(1, 3, "kind", StringValue("rail")); err == nil {
+		t.Error("an attribute is stored on a missing edge")
+	}
+
+	g.RemoveEdge(1, 2)
+	if _, ok := g.EdgeAttribute(1, 2, "kind"); ok {
+		t.Error("the removed edge keeps its attributes")
+	}
+	if err := g.SetEdgeAttribute(2, 3, "kind", StringValue("rail")); err != nil {
+		t.Fatal(err)
+	}
+	g.RemoveNode(3)
+	g.AddEdge(2, 3, 1)
+	if _, ok := g.EdgeAttribute(2, 3, "kind"); ok {
+		t.Error("the edges of a removed node keep their attributes")
+	}
+
+	// Directed edges have one set of attributes per direction
+	d := NewGraph(Directed(OutDegree))
+	d.AddEdge(1, 2, 1)
+	d.AddEdge(2, 1, 1)
+	if err := d.SetEdgeAttribute(1, 2, "kind", StringValue("up")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.EdgeAttribute(2, 1, "kind"); ok {
+		t.Error("the attribute of 1 -> 2 is found on 2 -> 1")
+	}
+}
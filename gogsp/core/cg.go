@@ -0,0 +1,49 @@
+// cg.go contains the conjugate gradient method, which solves symmetric positive definite systems A x = b from
+// products with A alone. The shift-invert eigensolver and the reconstruction of filter bank frames both rely on it.
+
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// ConjugateGradient solves A x = b for a symmetric positive definite operator A, starting from x = 0, until the
+// residual is below tolerance times the norm of b. It returns x with the number of products with A. If the method
+// does not converge in maxIterations, the last iterate is returned with an error.
+func ConjugateGradient(apply func([]float64) ([]float64, error), b []float64, tolerance float64, maxIterations int) ([]float64, int, error) {
+	x := make([]float64, len(b))
+	r := make([]float64, len(b))
+	copy(r, b)
+	p := make([]float64, len(b))
+	copy(p, b)
+
+	bNorm := math.Sqrt(dotProduct(b, b))
+	if bNorm == 0 {
+		return x, 0, nil
+	}
+	rr := dotProduct(r, r)
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		if math.Sqrt(rr) <= tolerance*bNorm {
+			return x, iteration, nil
+		}
+		ap, err := apply(p)
+		if err != nil {
+			return nil, iteration, err
+		}
+		alpha := rr / dotProduct(p, ap)
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+		}
+		rrNext := dotProduct(r, r)
+		for i := range p {
+			p[i] = r[i] + rrNext/rr*p[i]
+		}
+		rr = rrNext
+	}
+	if math.Sqrt(rr) <= tolerance*bNorm {
+		return x, maxIterations, nil
+	}
+	return x, maxIterations, fmt.Errorf("conjugate gradients did not converge in %d iterations, relative residual %g", maxIterations, math.Sqrt(rr)/bNorm)
+}
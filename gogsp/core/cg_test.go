@@ -0,0 +1,42 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConjugateGradient(t *testing.T) {
+	// The combinatorial Laplacian of a path plus the identity is positive definite
+	g := paths(1, 30)
+	laplacian := g.SparseLaplacian()
+	apply := func(v []float64) ([]float64, error) {
+		av := laplacian.MulVec(v)
+		for i := range av {
+			av[i] += v[i]
+		}
+		return av, nil
+	}
+	want := randomVector(30, 1)
+	b, _ := apply(want)
+	x, iterations, err := ConjugateGradient(apply, b, 1e-12, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := maxDifference(x, want); d > 1e-9 {
+		t.Errorf("the solution is off by %g after %d iterations", d, iterations)
+	}
+	if iterations == 0 || iterations > 30 {
+		t.Errorf("took %d iterations on 30 unknowns", iterations)
+	}
+
+	if x, _, err := ConjugateGradient(apply, make([]float64, 30), 1e-12, 100); err != nil || maxDifference(x, make([]float64, 30)) != 0 {
+		t.Errorf("b = 0 gives %v, %v", x, err)
+	}
+	if _, iterations, err := ConjugateGradient(apply, b, 1e-12, 2); err == nil || iterations != 2 {
+		t.Errorf("2 iterations give %d iterations and %v", iterations, err)
+	}
+	failure := errors.New("failure")
+	if _, _, err := ConjugateGradient(func([]float64) ([]float64, error) { return nil, failure }, b, 1e-12, 100); !errors.Is(err, failure) {
+		t.Errorf("a failing operator gives %v", err)
+	}
+}
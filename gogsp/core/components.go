@@ -0,0 +1,261 @@
+// components.go contains the connected components of a graph.
+// Components are returned as one label per node in matrix order, like a Signal, numbered from 0 in the order of
+// their first node. All traversals are iterative, so long paths cannot overflow the stack.
+
+package core
+
+import "fmt"
+
+// neighbours returns the adjacency of the graph by matrix index, following directed edges both ways if undirected
+// is true.
+func (g *Graph) neighbours(undirected bool) [][]int {
+	nodes := g.Nodes()
+	adjacency := make([][]int, len(nodes))
+	for i, node := range nodes {
+		for _, edge := range g.AdjacencyList[node] {
+			j, ok := g.NodeIndex(edge.Node)
+			if !ok {
+				continue
+			}
+			adjacency[i] = append(adjacency[i], j)
+			if undirected && g.directed {
+				adjacency[j] = append(adjacency[j], i)
+			}
+		}
+	}
+	return adjacency
+}
+
+// ConnectedComponents labels the connected components of the graph and returns the labels with their number.
+// Edge directions are ignored, so for directed graphs these are the weakly connected components.
+func (g *Graph) ConnectedComponents() (labels []int, count int) {
+	adjacency := g.neighbours(true)
+	labels = make([]int, len(adjacency))
+	for i := range labels {
+		labels[i] = -1
+	}
+	stack := make([]int, 0)
+	for start := range adjacency {
+		if labels[start] >= 0 {
+			continue
+		}
+		labels[start] = count
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, j := range adjacency[i] {
+				if labels[j] < 0 {
+					labels[j] = count
+					stack = append(stack, j)
+				}
+			}
+		}
+		count++
+	}
+	return labels, count
+}
+
+// WeaklyConnectedComponents labels the components of the graph with edge directions ignored. It is the same as
+// ConnectedComponents, and is provided for symmetry with StronglyConnectedComponents.
+func (g *Graph) WeaklyConnectedComponents() (labels []int, count int) {
+	return g.ConnectedComponents()
+}
+
+// StronglyConnectedComponents labels the strongly connected components of the graph, the largest sets of nodes
+// that can all reach each other along edge directions, with Tarjan's algorithm. For undirected graphs they are the
+// connected components.
+func (g *Graph) StronglyConnectedComponents() (labels []int, count int) {
+	adjacency := g.neighbours(false)
+	n := len(adjacency)
+	index := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+	tarjan := make([]int, n)
+	next, found := 0, 0
+	var stack []int
+
+	// frame is a node of the explicit DFS with the position of the next edge to explore
+	type frame struct {
+		node, edge int
+	}
+	for start := 0; start < n; start++ {
+		if index[start] >= 0 {
+			continue
+		}
+		calls := []frame{{start, 0}}
+		index[start], low[start] = next, next
+		next++
+		stack = append(stack, start)
+		onStack[start] = true
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			v := top.node
+			if top.edge < len(adjacency[v]) {
+				w := adjacency[v][top.edge]
+				top.edge++
+				if index[w] < 0 {
+					index[w], low[w] = next, next
+					next++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{w, 0})
+				} else if onStack[w] && index[w] < low[v] {
+					low[v] = index[w]
+				}
+				continue
+			}
+			// All edges of v are explored: return to its parent, closing its component if v is a root
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				if low[v] < low[parent] {
+					low[parent] = low[v]
+				}
+			}
+			if low[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					tarjan[w] = found
+					if w == v {
+						break
+					}
+				}
+				found++
+			}
+		}
+	}
+
+	// Tarjan closes components in reverse topological order, renumber them by first node
+	renumber := make([]int, found)
+	for i := range renumber {
+		renumber[i] = -1
+	}
+	labels = make([]int, n)
+	for i := range labels {
+		if renumber[tarjan[i]] < 0 {
+			renumber[tarjan[i]] = count
+			count++
+		}
+		labels[i] = renumber[tarjan[i]]
+	}
+	return labels, count
+}
+
+// IsWeaklyConnected reports whether the graph is connected when edge directions are ignored.
+func (g *Graph) IsWeaklyConnected() bool {
+	_, count := g.ConnectedComponents()
+	return count <= 1
+}
+
+// IsStronglyConnected reports whether every node can reach every other one along edge directions.
+func (g *Graph) IsStronglyConnected() bool {
+	_, count := g.StronglyConnectedComponents()
+	return count <= 1
+}
+
+// Subgraph returns the subgraph induced by the given nodes, i.e. the nodes and all edges between them, with the
+// same options, labels, coordinates and attributes. The nodes keep their IDs, so that labels, files and signals
+// written from the subgraph still name them as in g, and they take the matrix indices 0..k-1 in the given order.
+// The second result lists them in that order: node mapping[i] is at matrix index i of the subgraph.
+func (g *Graph) Subgraph(nodes []Node) (*Graph, []Node, error) {
+	kept := make(map[Node]bool, len(nodes))
+	for _, n := range nodes {
+		if _, present := g.AdjacencyList[n]; !present {
+			return nil, nil, fmt.Errorf("node %v is not in the graph", n)
+		}
+		if kept[n] {
+			return nil, nil, fmt.Errorf("node %v is given twice", n)
+		}
+		kept[n] = true
+	}
+
+	sub := NewGraph(WithLaplacian(g.laplacian))
+	sub.directed, sub.degree = g.directed, g.degree
+	for _, n := range nodes {
+		sub.AddNode(n)
+		if label, ok := g.labels[n]; ok {
+			sub.setLabel(n, label)
+		}
+		if c, ok := g.coordinates[n]; ok {
+			sub.coordinates = setCoordinate(sub.coordinates, n, c)
+		}
+	}
+	for _, n := range nodes {
+		for _, edge := range g.AdjacencyList[n] {
+			if kept[edge.Node] {
+				// Both directions of an undirected edge are stored, so copying arcs copies them both
+				sub.setArc(n, edge.Node, edge.Weight)
+			}
+		}
+	}
+
+	// Attribute kinds are copied even when no value survives, so that files written from the subgraph declare them
+	sub.nodeAttributes.kinds = copyKinds(g.nodeAttributes.kinds)
+	sub.edgeAttributes.kinds = copyKinds(g.edgeAttributes.kinds)
+	sub.nodeAttributes.values = make(map[Node]map[string]Value)
+	sub.edgeAttributes.values = make(map[arc]map[string]Value)
+	for _, n := range nodes {
+		for key, v := range g.nodeAttributes.values[n] {
+			_ = sub.nodeAttributes.set(n, key, v)
+		}
+	}
+	for a, values := range g.edgeAttributes.values {
+		if !kept[a.from] || !kept[a.to] {
+			continue
+		}
+		for key, v := range values {
+			_ = sub.edgeAttributes.set(a, key, v)
+		}
+	}
+
+	mapping := make([]Node, len(nodes))
+	copy(mapping, nodes)
+	return sub, mapping, nil
+}
+
+func setCoordinate(coordinates map[Node][]float64, n Node, c []float64) map[Node][]float64 {
+	if coordinates == nil {
+		coordinates = make(map[Node][]float64)
+	}
+	coordinates[n] = append([]float64(nil), c...)
+	return coordinates
+}
+
+func copyKinds(kinds map[string]AttributeKind) map[string]AttributeKind {
+	copied := make(map[string]AttributeKind, len(kinds))
+	for key, kind := range kinds {
+		copied[key] = kind
+	}
+	return copied
+}
+
+// LargestComponent returns the subgraph induced by the largest connected component, ignoring edge directions, with
+// its nodes in matrix order, see Subgraph. Ties go to the component of the earliest node.
+func (g *Graph) LargestComponent() (*Graph, []Node) {
+	labels, count := g.ConnectedComponents()
+	sizes := make([]int, count)
+	for _, label := range labels {
+		sizes[label]++
+	}
+	largest := 0
+	for c := range sizes {
+		if sizes[c] > sizes[largest] {
+			largest = c
+		}
+	}
+	nodes := make([]Node, 0)
+	for i, node := range g.Nodes() {
+		if labels[i] == largest {
+			nodes = append(nodes, node)
+		}
+	}
+	// The nodes come from g, so Subgraph cannot fail
+	sub, mapping, _ := g.Subgraph(nodes)
+	return sub, mapping
+}
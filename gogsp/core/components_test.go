@@ -0,0 +1,116 @@
+package core
+
+import "testing"
+
+func checkLabels(t *testing.T, what string, got []int, count int, want []int, wantCount int) {
+	t.Helper()
+	if count != wantCount || len(got) != len(want) {
+		t.Fatalf("%s: got %d components %v, want %d components %v", what, count, got, wantCount, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got labels %v, want %v", what, got, want)
+		}
+	}
+}
+
+func TestComponentsDirected(t *testing.T) {
+	// The cycle 0 -> 1 -> 2 -> 0 leads to the cycle 3 <-> 4, which 5 points to, and 6 is isolated
+	g := NewGraph(Directed(OutDegree))
+	for _, e := range [][2]Node{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 3}, {5, 3}} {
+		g.AddEdge(e[0], e[1], 1)
+	}
+	g.AddNode(6)
+
+	labels, count := g.WeaklyConnectedComponents()
+	checkLabels(t, "weak", labels, count, []int{0, 0, 0, 0, 0, 0, 1}, 2)
+	labels, count = g.StronglyConnectedComponents()
+	checkLabels(t, "strong", labels, count, []int{0, 0, 0, 1, 1, 2, 3}, 4)
+	if g.IsWeaklyConnected() || g.IsStronglyConnected() {
+		t.Error("the graph is reported connected")
+	}
+
+	// Undirected graphs have the same weak and strong components
+	u := paths(3, 4)
+	weak, weakCount := u.ConnectedComponents()
+	strong, strongCount := u.StronglyConnectedComponents()
+	checkLabels(t, "undirected", strong, strongCount, weak, weakCount)
+	if weakCount != 3 {
+		t.Errorf("got %d components of 3 paths", weakCount)
+	}
+}
+
+// A path of 10^5 nodes would overflow a recursive traversal with a small stack.
+func TestComponentsLongPath(t *testing.T) {
+	const n = 100000
+	g := NewGraph(Directed(OutDegree))
+	for i := 0; i+1 < n; i++ {
+		g.AddEdge(Node(i), Node(i+1), 1)
+	}
+	if _, count := g.ConnectedComponents(); count != 1 {
+		t.Errorf("got %d weak components, want 1", count)
+	}
+	if _, count := g.StronglyConnectedComponents(); count != n {
+		t.Errorf("got %d strong components, want %d", count, n)
+	}
+
+	// Closing the path into a cycle makes it strongly connected
+	g.AddEdge(n-1, 0, 1)
+	if !g.IsStronglyConnected() {
+		t.Error("the directed cycle is not strongly connected")
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(10, 20, 1)
+	g.AddEdge(20, 30, 2)
+	g.AddEdge(30, 10, 3)
+	g.AddEdge(40, 50, 4)
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(g.SetLabel(20, "b"))
+	check(g.SetCoordinates(30, 1, 2))
+	check(g.SetNodeAttribute(10, "size", IntValue(7)))
+	check(g.SetEdgeAttribute(30, 20, "kind", StringValue("road")))
+	check(g.SetEdgeAttribute(40, 50, "kind", StringValue("rail")))
+
+	sub, mapping, err := g.Subgraph([]Node{30, 10, 20})
+	check(err)
+	want := []Node{30, 10, 20}
+	for i, n := range sub.Nodes() {
+		if n != want[i] || mapping[i] != want[i] {
+			t.Fatalf("got nodes %v and mapping %v, want %v", sub.Nodes(), mapping, want)
+		}
+	}
+	if sub.Label(10) != "10" || sub.Label(20) != "b" {
+		t.Errorf("got labels %q and %q, want 10 and b", sub.Label(10), sub.Label(20))
+	}
+	if c, ok := sub.Coordinates(30); !ok || c[0] != 1 || c[1] != 2 {
+		t.Errorf("node 30 is at %v", c)
+	}
+	if v, ok := sub.NodeAttribute(10, "size"); !ok || v.String() != "7" {
+		t.Errorf("node 10 has size %v", v)
+	}
+	if v, ok := sub.EdgeAttribute(20, 30, "kind"); !ok || v.String() != "road" {
+		t.Errorf("edge 20 - 30 has kind %v", v)
+	}
+	if !sub.HasEdge(10, 30) || !sub.HasEdge(30, 10) || sub.HasEdge(40, 50) {
+		t.Errorf("got the edges %v", sub.AdjacencyList)
+	}
+	if _, _, err := g.Subgraph([]Node{10, 10}); err == nil {
+		t.Error("a repeated node is accepted")
+	}
+	if _, _, err := g.Subgraph([]Node{60}); err == nil {
+		t.Error("a missing node is accepted")
+	}
+
+	largest, nodes := g.LargestComponent()
+	if len(nodes) != 3 || nodes[0] != 10 || nodes[1] != 20 || nodes[2] != 30 || len(largest.Nodes()) != 3 {
+		t.Errorf("the largest component has the nodes %v", nodes)
+	}
+}
@@ -0,0 +1,55 @@
+// coords.go contains the optional coordinates of the nodes, used to draw a graph and by geometric constructions.
+// Coordinates are not part of the graph structure: setting them does not invalidate any matrix.
+
+package core
+
+import "fmt"
+
+// SetCoordinates places an existing node at the given position. Every node of a graph has the same number of
+// coordinates, fixed by the first call.
+func (g *Graph) SetCoordinates(n Node, coordinates ...float64) error {
+	if _, present := g.AdjacencyList[n]; !present {
+		return fmt.Errorf("node %v is not in the graph", n)
+	}
+	if len(coordinates) == 0 {
+		return fmt.Errorf("node %v needs at least one coordinate", n)
+	}
+	if d := g.CoordinateDims(); d != 0 && d != len(coordinates) {
+		return fmt.Errorf("node %v has %d coordinates but the graph has %d", n, len(coordinates), d)
+	}
+	if g.coordinates == nil {
+		g.coordinates = make(map[Node][]float64)
+	}
+	g.coordinates[n] = append([]float64(nil), coordinates...)
+	return nil
+}
+
+// Coordinates returns the position of the node, if it has one.
+func (g *Graph) Coordinates(n Node) ([]float64, bool) {
+	c, ok := g.coordinates[n]
+	if !ok {
+		return nil, false
+	}
+	return append([]float64(nil), c...), true
+}
+
+// CoordinateDims returns the number of coordinates of the nodes, 0 if no node has any.
+func (g *Graph) CoordinateDims() int {
+	for _, c := range g.coordinates {
+		return len(c)
+	}
+	return 0
+}
+
+// HasCoordinates reports whether every node of the graph has a position.
+func (g *Graph) HasCoordinates() bool {
+	if len(g.AdjacencyList) == 0 {
+		return false
+	}
+	for n := range g.AdjacencyList {
+		if _, ok := g.coordinates[n]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+// Author: Harish Raju
+// github: github.com/probably-harish
+
+// Package core holds the types shared by every other GoGSP package: the Graph, the Signal defined over its
+// nodes and the spectral basis used by the graph Fourier transform.
+// graphs, signals, filters and plot all build on top of core, and core depends on none of them.
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type Node int
+
+type Weight float64
+
+type Edge struct {
+	Node   Node
+	Weight Weight
+}
+
+// DegreeKind selects which degree is put on the diagonal of the Laplacian of a directed graph.
+type DegreeKind int
+
+const (
+	// OutDegree builds L = D_out - W, whose rows sum to zero
+	OutDegree DegreeKind = iota
+	// InDegree builds L = D_in - W, whose columns sum to zero
+	InDegree
+)
+
+type Graph struct {
+	// AdjacencyList holds the edges leaving every node. Edges may be edited in place, followed by InvalidateBasis,
+	// but nodes must be added and removed with AddNode and RemoveNode, which keep the matrix order
+	AdjacencyList   map[Node][]Edge
+	WeightedGraph   [][]Weight
+	LaplacianMatrix [][]Weight
+	AdjacencyMatrix [][]Weight
+
+	// directed graphs store each edge once, undirected graphs store it in both adjacency lists
+	directed bool
+	// degree is only used by directed graphs, undirected graphs have equal in- and out-degrees
+	degree DegreeKind
+	// laplacian is the kind of Laplacian built from the graph, see WithLaplacian
+	laplacian LaplacianKind
+
+	// order and index map between node IDs and matrix indices, see Nodes
+	order []Node
+	index map[Node]int
+	// generation counts the changes of order, so that a GraphSignal can tell when to realign
+	generation uint64
+	// stamps holds the generation at which each node entered order, which tells a node removed and added again
+	// apart from the one that was removed
+	stamps map[Node]uint64
+	// nextID is one more than the largest node ID the graph has held, the ID AddLabeledNode gives
+	nextID Node
+	// labels and byLabel hold the optional string names of the nodes, see Label
+	labels  map[Node]string
+	byLabel map[string]Node
+	// coordinates holds the optional positions of the nodes, see SetCoordinates
+	coordinates map[Node][]float64
+	// nodeAttributes and edgeAttributes hold the typed attributes, see SetNodeAttribute
+	nodeAttributes attributeTable[Node]
+	edgeAttributes attributeTable[arc]
+
+	// sparseWeights and sparseLaplacian cache the CSR matrices, see Graph.SparseLaplacian
+	sparseWeights   *CSR
+	sparseLaplacian *CSR
+
+	// basis caches the eigendecomposition of the Laplacian, see Graph.Basis
+	basis *Basis
+}
+
+// GraphOption configures a Graph at construction time.
+type GraphOption func(*Graph)
+
+// Directed makes the graph directed: AddEdge(n1, n2, w) only adds n1 -> n2, and the Laplacian is built from the
+// given degree. Directed Laplacians are not symmetric, so they have no orthonormal Fourier basis.
+func Directed(degree DegreeKind) GraphOption {
+	return func(g *Graph) {
+		g.directed = true
+		g.degree = degree
+	}
+}
+
+// NewGraph returns an empty graph. Graphs are undirected unless the Directed option is given.
+func NewGraph(opts ...GraphOption) *Graph {
+	g := &Graph{
+		AdjacencyList:   make(map[Node][]Edge),
+		WeightedGraph:   nil,
+		LaplacianMatrix: nil,
+		AdjacencyMatrix: nil,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// IsDirected reports whether the graph was created with the Directed option.
+func (g *Graph) IsDirected() bool {
+	return g.directed
+}
+
+// Degree returns the degree used on the diagonal of the Laplacian of a directed graph.
+func (g *Graph) Degree() DegreeKind {
+	return g.degree
+}
+
+func (g *Graph) AddNode(n Node) {
+	if _, present := g.AdjacencyList[n]; !present {
+		// Extend the index first, syncIndex would otherwise see an unknown node and rebuild the whole index
+		g.appendIndex(n)
+		g.AdjacencyList[n] = []Edge{}
+		g.invalidate()
+	}
+}
+
+// AddEdge adds an edge from n1 to n2, creating the nodes if needed. In an undirected graph the edge from n2 to n1
+// is added as well. Adding an edge that already exists overwrites its weight.
+func (g *Graph) AddEdge(n1, n2 Node, weight Weight) {
+	g.AddNode(n1)
+	g.AddNode(n2)
+	g.setArc(n1, n2, weight)
+	if !g.directed {
+		g.setArc(n2, n1, weight)
+	}
+	g.invalidate()
+}
+
+// setArc sets the weight of the n1 -> n2 entry of the adjacency list, appending it if it is missing.
+func (g *Graph) setArc(n1, n2 Node, weight Weight) {
+	edges := g.AdjacencyList[n1]
+	for i := range edges {
+		if edges[i].Node == n2 {
+			edges[i].Weight = weight
+			return
+		}
+	}
+	g.AdjacencyList[n1] = append(edges, Edge{n2, weight})
+}
+
+// removeArc deletes the n1 -> n2 entry of the adjacency list and reports whether it was present.
+func (g *Graph) removeArc(n1, n2 Node) bool {
+	edges := g.AdjacencyList[n1]
+	for i := range edges {
+		if edges[i].Node == n2 {
+			g.AdjacencyList[n1] = append(edges[:i], edges[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// HasEdge reports whether the graph has an edge from n1 to n2.
+func (g *Graph) HasEdge(n1, n2 Node) bool {
+	for _, edge := range g.AdjacencyList[n1] {
+		if edge.Node == n2 {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEdgeWeight changes the weight of an existing edge, in both directions for undirected graphs.
+func (g *Graph) SetEdgeWeight(n1, n2 Node, weight Weight) error {
+	if !g.HasEdge(n1, n2) {
+		return fmt.Errorf("no edge from node %v to node %v", n1, n2)
+	}
+	g.setArc(n1, n2, weight)
+	if !g.directed {
+		g.setArc(n2, n1, weight)
+	}
+	g.invalidate()
+	return nil
+}
+
+// RemoveEdge removes the edge from n1 to n2, and the edge from n2 to n1 for undirected graphs.
+// It reports whether the edge was present.
+func (g *Graph) RemoveEdge(n1, n2 Node) bool {
+	if !g.removeArc(n1, n2) {
+		return false
+	}
+	if !g.directed {
+		g.removeArc(n2, n1)
+	}
+	g.dropEdgeAttributes(n1, n2)
+	g.invalidate()
+	return true
+}
+
+// RemoveNode removes the node together with all of its outgoing and incoming edges.
+// It returns the matrix index the node had before removal so that signals on the graph can be realigned with
+// Signal.Delete; the nodes after it move down by one index and keep their relative order.
+// ok is false if the node was not in the graph.
+func (g *Graph) RemoveNode(n Node) (index int, ok bool) {
+	index, ok = g.NodeIndex(n)
+	if !ok {
+		return -1, false
+	}
+	delete(g.AdjacencyList, n)
+	for other := range g.AdjacencyList {
+		g.removeArc(other, n)
+	}
+	g.removeIndex(index)
+	g.removeLabel(n)
+	delete(g.coordinates, n)
+	g.dropNodeAttributes(n)
+	g.invalidate()
+	return index, true
+}
+
+// invalidate drops every matrix derived from the adjacency list after the graph has been mutated.
+// The matrices are rebuilt by the Update functions, and the basis by the next call to Basis.
+func (g *Graph) invalidate() {
+	g.WeightedGraph = nil
+	g.AdjacencyMatrix = nil
+	g.LaplacianMatrix = nil
+	g.sparseWeights = nil
+	g.sparseLaplacian = nil
+	g.InvalidateBasis()
+}
+
+func (g *Graph) PrintGraph() {
+	for _, node := range g.Nodes() {
+		fmt.Printf("\nNode %v:", g.Label(node))
+		for _, edge := range g.AdjacencyList[node] {
+			weight := strconv.FormatFloat(float64(edge.Weight), 'f', 2, 64)
+			fmt.Printf("\n%v   Weight: %v", g.Label(edge.Node), weight)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+// PrintAdjacencyMatrix prints the adjacency matrix.
+func (g *Graph) PrintAdjacencyMatrix() {
+	fmt.Println("Adjacency Matrix:")
+	nodes := g.Nodes()
+	for i, row := range g.AdjacencyMatrix {
+		fmt.Printf("Node %v :", nodes[i])
+		for _, weight := range row {
+			// Format the weights to two decimal places
+			fmt.Printf(" %.2f", weight)
+		}
+		fmt.Println()
+	}
+}
+
+// PrintLaplacianMatrix prints the Laplacian matrix.
+func (g *Graph) PrintLaplacianMatrix() {
+	fmt.Println("Laplacian Matrix:")
+	nodes := g.Nodes()
+	for i, row := range g.LaplacianMatrix {
+		fmt.Printf("Node %v :", nodes[i])
+		for _, weight := range row {
+			// Format the weights to two decimal places
+			fmt.Printf(" %.2f", weight)
+		}
+		fmt.Println()
+	}
+}
+
+// PrintWeightedGraph prints the weighted graph matrix.
+func (g *Graph) PrintWeightedGraph() {
+	fmt.Println("Weighted Graph:")
+	nodes := g.Nodes()
+	for i, row := range g.WeightedGraph {
+		fmt.Printf("Node %v :", nodes[i])
+		for _, weight := range row {
+			// Format the weights to two decimal places
+			fmt.Printf(" %.2f", weight)
+		}
+		fmt.Println()
+	}
+}
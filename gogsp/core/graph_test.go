@@ -0,0 +1,101 @@
+package core
+
+import "testing"
+
+func TestDirectedEdges(t *testing.T) {
+	d := NewGraph(Directed(OutDegree))
+	d.AddEdge(1, 2, 1)
+	if !d.IsDirected() || !d.HasEdge(1, 2) || d.HasEdge(2, 1) {
+		t.Errorf("the directed edge 1 -> 2 gives the arcs %v", d.AdjacencyList)
+	}
+	u := NewGraph()
+	u.AddEdge(1, 2, 1)
+	if u.IsDirected() || !u.HasEdge(1, 2) || !u.HasEdge(2, 1) {
+		t.Errorf("the undirected edge 1 - 2 gives the arcs %v", u.AdjacencyList)
+	}
+
+	// Weights change in both directions of an undirected edge and in one of a directed one
+	if err := u.SetEdgeWeight(2, 1, 4); err != nil {
+		t.Fatal(err)
+	}
+	if u.AdjacencyList[1][0].Weight != 4 {
+		t.Errorf("edge 1 - 2 has weight %v, want 4", u.AdjacencyList[1][0].Weight)
+	}
+	if err := d.SetEdgeWeight(2, 1, 4); err == nil {
+		t.Error("the missing arc 2 -> 1 gets a weight")
+	}
+
+	d.AddEdge(2, 1, 3)
+	if !d.RemoveEdge(1, 2) || d.HasEdge(1, 2) || !d.HasEdge(2, 1) {
+		t.Errorf("removing 1 -> 2 leaves the arcs %v", d.AdjacencyList)
+	}
+	if d.RemoveEdge(1, 2) {
+		t.Error("an arc is removed twice")
+	}
+
+	// Removing a node takes its incoming arcs with it
+	d.AddEdge(3, 2, 1)
+	if index, ok := d.RemoveNode(2); !ok || index != 1 {
+		t.Fatalf("removing node 2 gives index %d, %t", index, ok)
+	}
+	if len(d.AdjacencyList[3]) != 0 || len(d.Nodes()) != 2 {
+		t.Errorf("removing node 2 leaves the arcs %v", d.AdjacencyList)
+	}
+	if _, ok := d.RemoveNode(2); ok {
+		t.Error("a node is removed twice")
+	}
+}
+
+func TestMutationsDropMatrices(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(1, 2, 1)
+	for _, step := range []struct {
+		name   string
+		mutate func()
+	}{
+		{"AddNode", func() { g.AddNode(3) }},
+		{"AddEdge", func() { g.AddEdge(2, 3, 1) }},
+		{"SetEdgeWeight", func() { g.SetEdgeWeight(1, 2, 2) }},
+		{"RemoveEdge", func() { g.RemoveEdge(2, 3) }},
+		{"RemoveNode", func() { g.RemoveNode(3) }},
+	} {
+		g.UpdateWeightedGraph()
+		g.UpdateLaplacianMatrix()
+		g.UpdateAdjacencyMatrix()
+		step.mutate()
+		if g.WeightedGraph != nil || g.LaplacianMatrix != nil || g.AdjacencyMatrix != nil {
+			t.Errorf("%s keeps the dense matrices", step.name)
+		}
+	}
+}
+
+func TestSymmetricLaplacian(t *testing.T) {
+	d := NewGraph(Directed(InDegree))
+	d.AddEdge(1, 2, 1)
+	d.AddEdge(2, 1, 1)
+	d.UpdateWeightedGraph()
+	d.UpdateLaplacianMatrix()
+	// Even a directed graph with symmetric weights is refused
+	if _, err := d.LaplacianToMatSymDense(); err == nil {
+		t.Error("the Laplacian of a directed graph is accepted as symmetric")
+	}
+
+	u := NewGraph(WithLaplacian(RandomWalk))
+	u.AddEdge(1, 2, 1)
+	u.AddEdge(2, 3, 2)
+	u.UpdateWeightedGraph()
+	u.UpdateLaplacianMatrix()
+	if _, err := u.LaplacianToMatSymDense(); err == nil {
+		t.Error("the random-walk Laplacian is accepted as symmetric")
+	}
+	u.SetLaplacianKind(Combinatorial)
+	u.UpdateWeightedGraph()
+	u.UpdateLaplacianMatrix()
+	l, err := u.LaplacianToMatSymDense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.At(1, 1) != 3 || l.At(1, 2) != -2 || l.At(2, 1) != -2 {
+		t.Errorf("got the Laplacian %v", l)
+	}
+}
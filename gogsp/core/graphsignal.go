@@ -0,0 +1,139 @@
+// graphsignal.go contains GraphSignal, a signal bound to its graph.
+// A plain Signal is laid out in the matrix order of the graph at the time it was made, and silently goes out of line
+// when nodes are removed or added. A GraphSignal remembers the nodes its values belong to and the generation of the
+// graph's node order, its fingerprint, and realigns the values by node ID whenever the order has changed since:
+// values of removed nodes are dropped and new nodes get 0, even when they reuse the ID of a removed node.
+
+package core
+
+import "fmt"
+
+// GraphSignal is a signal bound to a graph, with one value per node of that graph.
+type GraphSignal struct {
+	graph *Graph
+	// nodes are the nodes of the values, the matrix order of the graph at generation, and stamps tell when each of
+	// them was added
+	nodes      []Node
+	stamps     []uint64
+	generation uint64
+	values     Signal
+}
+
+// NewGraphSignal returns a zero signal on the graph.
+func NewGraphSignal(g *Graph) *GraphSignal {
+	nodes := g.Nodes()
+	return &GraphSignal{graph: g, nodes: nodes, stamps: g.nodeStamps(), generation: g.generation, values: make(Signal, len(nodes))}
+}
+
+// BindSignal copies the values, in the current matrix order of the graph, into a signal bound to it. It returns a
+// SizeMismatchError unless there is one value per node.
+func BindSignal(g *Graph, values Signal) (*GraphSignal, error) {
+	if err := values.CheckSize(g); err != nil {
+		return nil, err
+	}
+	s := NewGraphSignal(g)
+	copy(s.values, values)
+	return s, nil
+}
+
+// align brings the values in line with the current node order of the graph.
+func (s *GraphSignal) align() {
+	g := s.graph
+	g.syncIndex()
+	if s.generation == g.generation {
+		return
+	}
+	values := make(Signal, len(g.order))
+	for k, n := range s.nodes {
+		// A node with another stamp was removed and added again since, its old value is gone
+		if i, ok := g.index[n]; ok && g.stamps[n] == s.stamps[k] {
+			values[i] = s.values[k]
+		}
+	}
+	s.nodes = append(s.nodes[:0], g.order...)
+	s.stamps = g.nodeStamps()
+	s.values = values
+	s.generation = g.generation
+}
+
+// Graph returns the graph the signal is bound to.
+func (s *GraphSignal) Graph() *Graph {
+	return s.graph
+}
+
+// Len returns the number of values, the number of nodes of the graph.
+func (s *GraphSignal) Len() int {
+	s.align()
+	return len(s.values)
+}
+
+// At returns the value at a node of the graph.
+func (s *GraphSignal) At(n Node) (float64, error) {
+	s.align()
+	i, ok := s.graph.index[n]
+	if !ok {
+		return 0, fmt.Errorf("node %v is not in the graph", n)
+	}
+	return s.values[i], nil
+}
+
+// SetAt sets the value at a node of the graph.
+func (s *GraphSignal) SetAt(n Node, value float64) error {
+	s.align()
+	i, ok := s.graph.index[n]
+	if !ok {
+		return fmt.Errorf("node %v is not in the graph", n)
+	}
+	s.values[i] = value
+	return nil
+}
+
+// AtLabel returns the value at the node with the given label, see Graph.NodeByLabel.
+func (s *GraphSignal) AtLabel(label string) (float64, error) {
+	n, ok := s.graph.NodeByLabel(label)
+	if !ok {
+		return 0, fmt.Errorf("no node labelled %q in the graph", label)
+	}
+	return s.At(n)
+}
+
+// Values returns a copy of the values in the current matrix order of the graph, e.g. for a transform or a filter.
+func (s *GraphSignal) Values() Signal {
+	s.align()
+	return append(Signal(nil), s.values...)
+}
+
+// SetValues replaces the values, given in the current matrix order of the graph. It returns a SizeMismatchError
+// unless there is one value per node.
+func (s *GraphSignal) SetValues(values Signal) error {
+	s.align()
+	if err := values.CheckSize(s.graph); err != nil {
+		return err
+	}
+	copy(s.values, values)
+	return nil
+}
+
+// On returns the values for use with the graph g, and ErrGraphMismatch if g is not the graph of the signal.
+func (s *GraphSignal) On(g *Graph) (Signal, error) {
+	if g != s.graph {
+		return nil, ErrGraphMismatch
+	}
+	return s.Values(), nil
+}
+
+// Map applies fn, such as a filter's Apply, to the values and binds the result to the same graph. It returns a
+// SizeMismatchError if fn does not return one value per node.
+func (s *GraphSignal) Map(fn func(Signal) (Signal, error)) (*GraphSignal, error) {
+	out, err := fn(s.Values())
+	if err != nil {
+		return nil, err
+	}
+	return BindSignal(s.graph, out)
+}
+
+// GraphFourierTransform returns the coefficients of the signal on the Fourier basis of its graph, see
+// Graph.GraphFourierTransform. Coefficient k belongs to the k-th graph frequency, not to a node, so it is a Signal.
+func (s *GraphSignal) GraphFourierTransform() (Signal, error) {
+	return s.graph.GraphFourierTransform(s.Values())
+}
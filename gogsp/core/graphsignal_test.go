@@ -0,0 +1,154 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func checkValues(t *testing.T, what string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", what, got, want)
+		}
+	}
+}
+
+func TestSignalAccess(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(10, 20, 1)
+	g.AddEdge(20, 30, 1)
+	if err := g.SetLabel(30, "c"); err != nil {
+		t.Fatal(err)
+	}
+	s := CreateSignal(3)
+	if err := s.SetSignal(g, []float64{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	var mismatch *SizeMismatchError
+	if err := s.SetSignal(g, []float64{1, 2}); !errors.As(err, &mismatch) || mismatch.Got != 2 || mismatch.Want != 3 {
+		t.Errorf("setting 2 values gives %v", err)
+	}
+	if err := CreateSignal(4).CheckSize(g); !errors.As(err, &mismatch) || mismatch.What != "signal" || mismatch.Of != "graph" {
+		t.Errorf("a signal of 4 values on 3 nodes gives %v", err)
+	}
+
+	if err := s.SetAt(g, 20, 5); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.At(g, 20); err != nil || v != 5 {
+		t.Errorf("node 20 has %v, %v, want 5", v, err)
+	}
+	if v, err := s.AtLabel(g, "c"); err != nil || v != 3 {
+		t.Errorf("node c has %v, %v, want 3", v, err)
+	}
+	if v, err := s.AtLabel(g, "10"); err != nil || v != 1 {
+		t.Errorf("node 10 has %v, %v, want 1", v, err)
+	}
+	if _, err := s.At(g, 40); err == nil {
+		t.Error("a missing node has a value")
+	}
+	if _, err := s.AtLabel(g, "30"); err == nil {
+		t.Error("a labeled node is found by its ID")
+	}
+
+	// Set and Get address matrix indices and fail out of range
+	if err := s.Set(2, 7); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Get(2); err != nil || v != 7 {
+		t.Errorf("index 2 has %v, %v, want 7", v, err)
+	}
+	if err := s.Set(3, 1); err == nil {
+		t.Error("index 3 of 3 values can be set")
+	}
+	if _, err := s.Get(-1); err == nil {
+		t.Error("index -1 has a value")
+	}
+
+	// Removing a node and deleting its index realigns a plain signal by hand
+	index, ok := g.RemoveNode(20)
+	if !ok || index != 1 {
+		t.Fatalf("removing node 20 gives index %d, %t", index, ok)
+	}
+	s = s.Delete(index)
+	checkValues(t, "after Delete", s, []float64{1, 7})
+	if v, err := s.At(g, 30); err != nil || v != 7 {
+		t.Errorf("node 30 has %v, %v after the removal, want 7", v, err)
+	}
+}
+
+func TestGraphSignalRealignment(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(10, 20, 1)
+	g.AddEdge(20, 30, 1)
+	if _, err := BindSignal(g, Signal{1, 2}); err == nil {
+		t.Error("binding 2 values to 3 nodes is accepted")
+	}
+	s, err := BindSignal(g, Signal{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a node drops its value and keeps the others with their nodes
+	g.RemoveNode(20)
+	if s.Len() != 2 {
+		t.Fatalf("got %d values on 2 nodes", s.Len())
+	}
+	checkValues(t, "after RemoveNode", s.Values(), []float64{1, 3})
+	if _, err := s.At(20); err == nil {
+		t.Error("the removed node still has a value")
+	}
+
+	// New nodes take the next matrix indices, 40 then 20, and start at 0
+	g.AddEdge(30, 40, 1)
+	g.AddNode(20)
+	checkValues(t, "after AddNode", s.Values(), []float64{1, 3, 0, 0})
+	if err := s.SetAt(20, 9); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetLabel(40, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetAt(40, 4); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.AtLabel("d"); err != nil || v != 4 {
+		t.Errorf("node d has %v, %v, want 4", v, err)
+	}
+	if v, err := s.At(20); err != nil || v != 9 {
+		t.Errorf("node 20 has %v, %v, want 9", v, err)
+	}
+
+	if err := s.SetValues(Signal{1}); err == nil {
+		t.Error("setting 1 value on 4 nodes is accepted")
+	}
+	if _, err := s.On(NewGraph()); !errors.Is(err, ErrGraphMismatch) {
+		t.Errorf("using the signal on another graph gives %v", err)
+	}
+	doubled, err := s.Map(func(x Signal) (Signal, error) {
+		for i := range x {
+			x[i] *= 2
+		}
+		return x, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkValues(t, "Map", doubled.Values(), []float64{2, 6, 8, 18})
+	checkValues(t, "the mapped signal", s.Values(), []float64{1, 3, 4, 9})
+	if _, err := s.Map(func(x Signal) (Signal, error) { return x[:1], nil }); err == nil {
+		t.Error("Map accepts a result of the wrong size")
+	}
+
+	// A node removed and added again between two accesses is a new node and starts at 0
+	g.RemoveNode(30)
+	g.AddEdge(30, 10, 1)
+	checkValues(t, "after re-adding node 30", s.Values(), []float64{1, 4, 9, 0})
+	if v, err := s.At(30); err != nil || v != 0 {
+		t.Errorf("the re-added node 30 has %v, %v, want 0", v, err)
+	}
+}
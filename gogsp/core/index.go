@@ -0,0 +1,204 @@
+// index.go contains the mapping between node IDs and matrix indices, and the optional string labels of nodes.
+// Node IDs are chosen by the user and can be any int; matrix index i is the i-th node added to the graph.
+// Every matrix built from the graph, and every Signal on it, is laid out in that order.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Nodes returns the nodes of the graph in matrix order, i.e. the order in which they were added.
+// Row and column i of every matrix of the graph, and entry i of a signal on it, belong to Nodes()[i].
+func (g *Graph) Nodes() []Node {
+	g.syncIndex()
+	nodes := make([]Node, len(g.order))
+	copy(nodes, g.order)
+	return nodes
+}
+
+// NodeIndex returns the matrix index of the node, see Nodes.
+func (g *Graph) NodeIndex(n Node) (int, bool) {
+	g.syncIndex()
+	i, ok := g.index[n]
+	if !ok {
+		return -1, false
+	}
+	return i, true
+}
+
+// NodeAt returns the node at the given matrix index.
+func (g *Graph) NodeAt(i int) (Node, error) {
+	g.syncIndex()
+	if i < 0 || i >= len(g.order) {
+		return 0, fmt.Errorf("index %d out of range for a graph with %d nodes", i, len(g.order))
+	}
+	return g.order[i], nil
+}
+
+// AddLabeledNode adds a node named by a string and returns the ID it was given, one more than the largest ID the
+// graph has held. Adding a name that is already in use returns the existing node, including the decimal ID of a node
+// without a label, which is its name, see Label.
+func (g *Graph) AddLabeledNode(label string) Node {
+	if n, ok := g.NodeByLabel(label); ok {
+		return n
+	}
+	g.syncIndex()
+	n := g.nextID
+	for {
+		// Only reached by nodes added to AdjacencyList directly, see syncIndex
+		if _, present := g.AdjacencyList[n]; !present {
+			break
+		}
+		n++
+	}
+	g.AddNode(n)
+	g.setLabel(n, label)
+	return n
+}
+
+// SetLabel names an existing node. Labels must be unique within a graph, and must not be the decimal ID of another
+// node without a label, which goes by that name, so that every name written to a file stands for a single node.
+func (g *Graph) SetLabel(n Node, label string) error {
+	if _, present := g.AdjacencyList[n]; !present {
+		return fmt.Errorf("node %v is not in the graph", n)
+	}
+	if other, ok := g.NodeByLabel(label); ok && other != n {
+		if _, labeled := g.labels[other]; !labeled {
+			return fmt.Errorf("label %q is the ID of node %v, which has no label", label, other)
+		}
+		return fmt.Errorf("label %q is already used by node %v", label, other)
+	}
+	g.removeLabel(n)
+	g.setLabel(n, label)
+	return nil
+}
+
+// Label returns the label of the node, or its decimal ID if it has none. SetLabel keeps the names of the nodes apart,
+// but AddNode cannot: adding a node whose decimal ID is the label of another one gives both the same name.
+func (g *Graph) Label(n Node) string {
+	if label, ok := g.labels[n]; ok {
+		return label
+	}
+	return strconv.Itoa(int(n))
+}
+
+// NodeByLabel returns the node with the given label. Nodes without a label are found by their decimal ID, written
+// as Label writes it, so "07" and "+7" do not name node 7.
+func (g *Graph) NodeByLabel(label string) (Node, bool) {
+	if n, ok := g.byLabel[label]; ok {
+		return n, true
+	}
+	id, err := strconv.Atoi(label)
+	if err != nil || strconv.Itoa(id) != label {
+		return 0, false
+	}
+	n := Node(id)
+	if _, present := g.AdjacencyList[n]; !present {
+		return 0, false
+	}
+	if _, labeled := g.labels[n]; labeled {
+		return 0, false
+	}
+	return n, true
+}
+
+func (g *Graph) setLabel(n Node, label string) {
+	if g.labels == nil {
+		g.labels = make(map[Node]string)
+		g.byLabel = make(map[string]Node)
+	}
+	g.labels[n] = label
+	g.byLabel[label] = n
+}
+
+func (g *Graph) removeLabel(n Node) {
+	if label, ok := g.labels[n]; ok {
+		delete(g.labels, n)
+		delete(g.byLabel, label)
+	}
+}
+
+// appendIndex gives a newly added node the next matrix index.
+func (g *Graph) appendIndex(n Node) {
+	g.syncIndex()
+	if _, ok := g.index[n]; ok {
+		return
+	}
+	g.index[n] = len(g.order)
+	g.order = append(g.order, n)
+	if n >= g.nextID {
+		g.nextID = n + 1
+	}
+	g.generation++
+	g.stamps[n] = g.generation
+}
+
+// removeIndex drops the node at matrix index i and moves the following nodes down by one.
+func (g *Graph) removeIndex(i int) {
+	delete(g.index, g.order[i])
+	delete(g.stamps, g.order[i])
+	g.order = append(g.order[:i], g.order[i+1:]...)
+	for j := i; j < len(g.order); j++ {
+		g.index[g.order[j]] = j
+	}
+	g.generation++
+}
+
+// syncIndex brings the index in line with AdjacencyList when nodes were added or removed by editing the map
+// directly. Nodes that are still present keep their order, and unknown nodes are appended sorted by ID.
+// Comparing every node on each call would make NodeIndex O(N), so only edits that change the number of nodes are
+// noticed: replacing a node by another in the map goes unseen, which is why nodes must be added and removed with
+// AddNode and RemoveNode, see Graph.AdjacencyList.
+func (g *Graph) syncIndex() {
+	if g.index == nil {
+		g.index = make(map[Node]int)
+		g.stamps = make(map[Node]uint64)
+	}
+	if len(g.order) == len(g.AdjacencyList) {
+		return
+	}
+	order := g.order[:0]
+	for _, n := range g.order {
+		if _, present := g.AdjacencyList[n]; present {
+			order = append(order, n)
+		} else {
+			delete(g.stamps, n)
+		}
+	}
+	known := make(map[Node]bool, len(order))
+	for _, n := range order {
+		known[n] = true
+	}
+	missing := make([]Node, 0)
+	for n := range g.AdjacencyList {
+		if !known[n] {
+			missing = append(missing, n)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	g.order = append(order, missing...)
+	g.index = make(map[Node]int, len(g.order))
+	for i, n := range g.order {
+		g.index[n] = i
+		if n >= g.nextID {
+			g.nextID = n + 1
+		}
+	}
+	g.generation++
+	for _, n := range missing {
+		g.stamps[n] = g.generation
+	}
+}
+
+// nodeStamps returns the stamp of every node in matrix order, see Graph.stamps.
+func (g *Graph) nodeStamps() []uint64 {
+	g.syncIndex()
+	stamps := make([]uint64, len(g.order))
+	for i, n := range g.order {
+		stamps[i] = g.stamps[n]
+	}
+	return stamps
+}
@@ -0,0 +1,79 @@
+package core
+
+import "testing"
+
+func TestAddLabeledNode(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(-4)
+	g.AddNode(5)
+	a := g.AddLabeledNode("a")
+	b := g.AddLabeledNode("b")
+	if a != 6 || b != 7 {
+		t.Fatalf("got IDs %v and %v, want 6 and 7", a, b)
+	}
+	if again := g.AddLabeledNode("a"); again != a {
+		t.Errorf("adding label a again gives %v, want %v", again, a)
+	}
+
+	// IDs are not reused after a removal
+	if _, ok := g.RemoveNode(b); !ok {
+		t.Fatal("node b is missing")
+	}
+	if c := g.AddLabeledNode("c"); c != 8 {
+		t.Errorf("got ID %v, want 8", c)
+	}
+
+	// Nodes added to the map directly are noticed when the number of nodes changes
+	g.AdjacencyList[20] = []Edge{}
+	if d := g.AddLabeledNode("d"); d != 21 {
+		t.Errorf("got ID %v, want 21", d)
+	}
+	want := []Node{-4, 5, 6, 8, 20, 21}
+	nodes := g.Nodes()
+	if len(nodes) != len(want) {
+		t.Fatalf("got nodes %v, want %v", nodes, want)
+	}
+	for i := range want {
+		if nodes[i] != want[i] {
+			t.Fatalf("got nodes %v, want %v", nodes, want)
+		}
+	}
+}
+
+func TestLabelCollisions(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(0)
+	g.AddNode(5)
+	if err := g.SetLabel(0, "5"); err == nil {
+		t.Error("node 0 is labelled with the ID of node 5")
+	}
+	if err := g.SetLabel(5, "5"); err != nil {
+		t.Errorf("node 5 cannot be labelled with its own ID: %v", err)
+	}
+	if err := g.SetLabel(0, "+5"); err != nil {
+		t.Errorf("a non-canonical integer is rejected: %v", err)
+	}
+	if n, ok := g.NodeByLabel("+5"); !ok || n != 0 {
+		t.Errorf("label +5 gives node %v, %t, want 0", n, ok)
+	}
+	if err := g.SetLabel(0, "5"); err == nil {
+		t.Error("node 0 takes the label of node 5")
+	}
+
+	// A labeled node no longer goes by its ID
+	if err := g.SetLabel(5, "five"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetLabel(0, "5"); err != nil {
+		t.Errorf("the ID of a labeled node is rejected: %v", err)
+	}
+	if n, ok := g.NodeByLabel("5"); !ok || n != 0 {
+		t.Errorf("label 5 gives node %v, %t, want 0", n, ok)
+	}
+
+	// Adding the name of an unlabeled node returns it
+	g.AddNode(9)
+	if n := g.AddLabeledNode("9"); n != 9 || g.Label(9) != "9" {
+		t.Errorf("adding the name 9 gives node %v, want the unlabeled node 9", n)
+	}
+}
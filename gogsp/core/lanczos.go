@@ -0,0 +1,449 @@
+// lanczos.go contains the Lanczos iteration on sparse symmetric matrices.
+// Lanczos builds an orthonormal basis V of the Krylov space span{x, A x, ..., A^(m-1) x} in which A is the
+// tridiagonal matrix T = V^T A V. The eigenvalues of a small T (the Ritz values) approximate the extreme
+// eigenvalues of A, and V f(T) V^T x approximates f(A) x, both after m << N sparse products.
+// It is used to bound the spectrum for Chebyshev filtering, to compute a few eigenpairs, and to filter signals,
+// all without a dense eigendecomposition. The basis is fully reorthogonalized, which costs O(N m) memory.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DefaultLanczosIterations and DefaultLanczosTolerance are used when LanczosOptions leaves them at zero.
+const (
+	DefaultLanczosIterations = 300
+	DefaultLanczosTolerance  = 1e-8
+)
+
+// LanczosOptions controls a Lanczos iteration.
+type LanczosOptions struct {
+	// MaxIterations caps the size of the Krylov space, and so the number of sparse products, per run.
+	// It is also capped by the size of the matrix
+	MaxIterations int
+	// Tolerance is the relative residual ||A v - θ v|| / ||A|| under which a Ritz pair is converged, or the relative
+	// change under which f(A) x is converged
+	Tolerance float64
+	// Seed seeds the random starting vectors, so that runs are reproducible
+	Seed int64
+}
+
+func (o LanczosOptions) withDefaults(n int) LanczosOptions {
+	if o.MaxIterations <= 0 {
+		o.MaxIterations = DefaultLanczosIterations
+	}
+	if o.MaxIterations > n {
+		o.MaxIterations = n
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = DefaultLanczosTolerance
+	}
+	return o
+}
+
+// LanczosDiagnostics reports how a Lanczos computation went.
+type LanczosDiagnostics struct {
+	// Iterations is the total number of sparse matrix-vector products
+	Iterations int
+	// Converged is false when MaxIterations was reached before the tolerance
+	Converged bool
+	// Residual is the largest residual norm of the returned eigenpairs, which bounds their distance to true
+	// eigenvalues, or the last relative change of f(A) x
+	Residual float64
+}
+
+// operator applies a symmetric linear map to a vector.
+type operator func([]float64) []float64
+
+// lanczosCheckInterval is the number of Lanczos steps between two convergence checks.
+const lanczosCheckInterval = 10
+
+// krylov holds a Lanczos decomposition A V = V T + beta_m v_m e_m^T.
+type krylov struct {
+	vectors [][]float64
+	alpha   []float64
+	beta    []float64
+}
+
+// lanczos runs the iteration from start, keeping the basis orthogonal to the locked vectors as well.
+// After a step done may be called with the current decomposition and stops the iteration when it returns true.
+// The iteration also stops, with an exact invariant subspace, when the next basis vector vanishes, or after
+// maxIterations steps; done is always called on the last step, so the state it keeps matches the returned
+// decomposition.
+func lanczos(apply operator, start []float64, locked [][]float64, maxIterations int, done func(*krylov) bool) (*krylov, bool, error) {
+	k := &krylov{}
+	v := make([]float64, len(start))
+	copy(v, start)
+	orthogonalize(v, locked)
+	norm := math.Sqrt(dotProduct(v, v))
+	if norm == 0 {
+		return nil, false, errors.New("lanczos starting vector lies in the locked subspace")
+	}
+	scale(v, 1/norm)
+
+	for j := 0; j < maxIterations; j++ {
+		k.vectors = append(k.vectors, v)
+		w := apply(v)
+		a := dotProduct(v, w)
+		k.alpha = append(k.alpha, a)
+
+		// Full reorthogonalization, twice is enough to reach machine precision
+		for pass := 0; pass < 2; pass++ {
+			orthogonalize(w, locked)
+			orthogonalize(w, k.vectors)
+		}
+		b := math.Sqrt(dotProduct(w, w))
+		k.beta = append(k.beta, b)
+
+		if b <= 1e-12*math.Max(1, math.Abs(a)) {
+			// The Krylov space is invariant: the Ritz pairs are exact
+			k.beta[j] = 0
+			done(k)
+			return k, true, nil
+		}
+		// Checking convergence factorizes T, so past the first steps it is only done every lanczosCheckInterval steps
+		last := j+1 == maxIterations
+		if (j < lanczosCheckInterval || (j+1)%lanczosCheckInterval == 0 || last) && done(k) {
+			return k, true, nil
+		}
+		v = w
+		scale(v, 1/b)
+	}
+	return k, false, nil
+}
+
+// ritz returns the eigenvalues of T in ascending order, the matching eigenvectors of T as columns, and the
+// residual norm of every Ritz pair.
+func (k *krylov) ritz() ([]float64, *mat.Dense, []float64, error) {
+	m := len(k.alpha)
+	t := mat.NewSymDense(m, nil)
+	for i := 0; i < m; i++ {
+		t.SetSym(i, i, k.alpha[i])
+		if i+1 < m {
+			t.SetSym(i, i+1, k.beta[i])
+		}
+	}
+	var es mat.EigenSym
+	if !es.Factorize(t, true) {
+		return nil, nil, nil, errors.New("failed to factorize the Lanczos tridiagonal matrix")
+	}
+	s := new(mat.Dense)
+	es.VectorsTo(s)
+	theta := es.Values(nil)
+	residuals := make([]float64, m)
+	for i := range residuals {
+		residuals[i] = math.Abs(k.beta[m-1] * s.At(m-1, i))
+	}
+	return theta, s, residuals, nil
+}
+
+// ritzVector returns V s for column i of the eigenvectors of T.
+func (k *krylov) ritzVector(s *mat.Dense, i int) []float64 {
+	v := make([]float64, len(k.vectors[0]))
+	for j, basis := range k.vectors {
+		c := s.At(j, i)
+		for l := range v {
+			v[l] += c * basis[l]
+		}
+	}
+	return v
+}
+
+// ExtremalEigenvalues estimates the smallest and largest eigenvalues of the symmetric matrix.
+// The true eigenvalues lie within Residual of the returned values.
+func (m *CSR) ExtremalEigenvalues(opts LanczosOptions) (lmin, lmax float64, diagnostics LanczosDiagnostics, err error) {
+	if m.Rows == 0 || m.Rows != m.Cols {
+		return 0, 0, diagnostics, fmt.Errorf("lanczos needs a non-empty square matrix, got %dx%d", m.Rows, m.Cols)
+	}
+	return extremalEigenvalues(m.MulVec, m.Rows, opts)
+}
+
+// extremalEigenvalues is ExtremalEigenvalues for an n x n operator.
+func extremalEigenvalues(apply operator, n int, opts LanczosOptions) (lmin, lmax float64, diagnostics LanczosDiagnostics, err error) {
+	opts = opts.withDefaults(n)
+
+	var failure error
+	done := func(k *krylov) bool {
+		theta, _, residuals, err := k.ritz()
+		if err != nil {
+			failure = err
+			return true
+		}
+		last := len(theta) - 1
+		lmin, lmax = theta[0], theta[last]
+		diagnostics.Residual = math.Max(residuals[0], residuals[last])
+		return diagnostics.Residual <= opts.Tolerance*math.Max(math.Abs(lmin), math.Abs(lmax))
+	}
+	k, converged, err := lanczos(apply, randomVector(n, opts.Seed), nil, opts.MaxIterations, done)
+	if err != nil {
+		return 0, 0, diagnostics, err
+	}
+	if failure != nil {
+		return 0, 0, diagnostics, failure
+	}
+	diagnostics.Iterations = len(k.alpha)
+	diagnostics.Converged = converged
+	return lmin, lmax, diagnostics, nil
+}
+
+// SmallestEigenpairs computes the k smallest eigenvalues of the symmetric matrix, in ascending order, and their
+// eigenvectors as the columns of an N x k matrix. Converged pairs are locked and the iteration is restarted
+// orthogonally to them, so that repeated eigenvalues are found with their multiplicity.
+func (m *CSR) SmallestEigenpairs(k int, opts LanczosOptions) ([]float64, *mat.Dense, LanczosDiagnostics, error) {
+	if m.Rows == 0 || m.Rows != m.Cols {
+		return nil, nil, LanczosDiagnostics{}, fmt.Errorf("lanczos needs a non-empty square matrix, got %dx%d", m.Rows, m.Cols)
+	}
+	return eigenpairs(m.MulVec, m.Rows, k, false, opts)
+}
+
+// eigenpairs computes the k smallest, or largest, eigenpairs of an n x n operator by Lanczos with locking.
+func eigenpairs(apply operator, n, k int, largest bool, opts LanczosOptions) ([]float64, *mat.Dense, LanczosDiagnostics, error) {
+	diagnostics := LanczosDiagnostics{Converged: true}
+	if k < 1 || k > n {
+		return nil, nil, diagnostics, fmt.Errorf("cannot compute %d eigenpairs of a %dx%d matrix", k, n, n)
+	}
+	opts = opts.withDefaults(n)
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	// order returns the indices of the values from the wanted end of the spectrum
+	order := func(values []float64) []int {
+		idx := make([]int, len(values))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool {
+			if largest {
+				return values[idx[a]] > values[idx[b]]
+			}
+			return values[idx[a]] < values[idx[b]]
+		})
+		return idx
+	}
+
+	// A single Krylov space holds only one eigenvector per eigenvalue, so runs are repeated orthogonally to the
+	// locked vectors until one finds nothing better than the k-th locked eigenvalue
+	var values []float64
+	var vectors [][]float64
+	for len(values) < n {
+		wanted := k - len(values)
+		if wanted < 1 {
+			wanted = 1
+		}
+		var failure error
+		done := func(kr *krylov) bool {
+			theta, _, residuals, err := kr.ritz()
+			if err != nil {
+				failure = err
+				return true
+			}
+			threshold := opts.Tolerance * math.Max(math.Max(math.Abs(theta[0]), math.Abs(theta[len(theta)-1])), 1e-300)
+			converged := 0
+			for _, i := range order(theta) {
+				if residuals[i] > threshold {
+					break
+				}
+				converged++
+			}
+			return converged >= wanted || len(theta) == n-len(values)
+		}
+		start := make([]float64, n)
+		for i := range start {
+			start[i] = rng.NormFloat64()
+		}
+		kr, converged, err := lanczos(apply, start, vectors, opts.MaxIterations, done)
+		if err != nil {
+			return nil, nil, diagnostics, err
+		}
+		if failure != nil {
+			return nil, nil, diagnostics, failure
+		}
+		diagnostics.Iterations += len(kr.alpha)
+
+		theta, s, residuals, err := kr.ritz()
+		if err != nil {
+			return nil, nil, diagnostics, err
+		}
+		threshold := opts.Tolerance * math.Max(math.Max(math.Abs(theta[0]), math.Abs(theta[len(theta)-1])), 1e-300)
+		kth := math.NaN()
+		if len(values) >= k {
+			kth = values[order(values)[k-1]]
+		}
+		found := 0
+		for _, i := range order(theta) {
+			if !math.IsNaN(kth) && (largest && theta[i] <= kth+threshold || !largest && theta[i] >= kth-threshold) {
+				break
+			}
+			// Lock the converged pairs; if none converged, take the best one and report it
+			if residuals[i] > threshold {
+				if found > 0 || converged {
+					break
+				}
+				diagnostics.Converged = false
+			}
+			values = append(values, theta[i])
+			vectors = append(vectors, kr.ritzVector(s, i))
+			diagnostics.Residual = math.Max(diagnostics.Residual, residuals[i])
+			found++
+			if residuals[i] > threshold {
+				break
+			}
+		}
+		if found == 0 || !converged && len(values) >= k {
+			break
+		}
+	}
+
+	idx := order(values)[:k]
+	sortedValues := make([]float64, k)
+	result := mat.NewDense(n, k, nil)
+	for j, i := range idx {
+		sortedValues[j] = values[i]
+		result.SetCol(j, vectors[i])
+	}
+	return sortedValues, result, diagnostics, nil
+}
+
+// Function approximates f(A) x by ||x|| V f(T) e_1, the Lanczos quadrature of f, for a symmetric matrix A.
+// The iteration stops when one more step changes the result by less than the relative tolerance.
+func (m *CSR) Function(f func(float64) float64, x []float64, opts LanczosOptions) ([]float64, LanczosDiagnostics, error) {
+	diagnostics := LanczosDiagnostics{}
+	if m.Rows != m.Cols || len(x) != m.Rows {
+		return nil, diagnostics, fmt.Errorf("lanczos needs a square matrix and a matching vector, got %dx%d and %d", m.Rows, m.Cols, len(x))
+	}
+	norm := math.Sqrt(dotProduct(x, x))
+	if norm == 0 {
+		diagnostics.Converged = true
+		return make([]float64, len(x)), diagnostics, nil
+	}
+	opts = opts.withDefaults(m.Rows)
+
+	var failure error
+	var coefficients, previous []float64
+	done := func(k *krylov) bool {
+		// z = S f(Θ) S^T e_1 holds the coordinates of f(A) x / ||x|| in the Lanczos basis
+		theta, s, _, err := k.ritz()
+		if err != nil {
+			failure = err
+			return true
+		}
+		z := make([]float64, len(theta))
+		for i := range theta {
+			weight := f(theta[i]) * s.At(0, i)
+			for j := range z {
+				z[j] += s.At(j, i) * weight
+			}
+		}
+		previous, coefficients = coefficients, z
+		if previous == nil {
+			return false
+		}
+		change, size := 0.0, 0.0
+		for j := range z {
+			d := z[j]
+			if j < len(previous) {
+				d -= previous[j]
+			}
+			change += d * d
+			size += z[j] * z[j]
+		}
+		diagnostics.Residual = math.Sqrt(change / math.Max(size, 1e-300))
+		return diagnostics.Residual <= opts.Tolerance
+	}
+	k, converged, err := lanczos(m.MulVec, x, nil, opts.MaxIterations, done)
+	if err != nil {
+		return nil, diagnostics, err
+	}
+	if failure != nil {
+		return nil, diagnostics, failure
+	}
+	diagnostics.Iterations = len(k.alpha)
+	diagnostics.Converged = converged
+	if k.beta[len(k.beta)-1] == 0 {
+		diagnostics.Residual = 0
+	}
+
+	y := make([]float64, len(x))
+	for j, basis := range k.vectors {
+		c := norm * coefficients[j]
+		for i := range y {
+			y[i] += c * basis[i]
+		}
+	}
+	return y, diagnostics, nil
+}
+
+// SpectrumEstimate holds Lanczos estimates of the extreme eigenvalues of a graph's Laplacian.
+type SpectrumEstimate struct {
+	LMin, LMax float64
+	LanczosDiagnostics
+}
+
+// UpperBound returns LMax plus its error bound, which is safe to use as the interval of a Chebyshev approximation.
+func (e SpectrumEstimate) UpperBound() float64 {
+	return e.LMax + e.Residual
+}
+
+// EstimateSpectrum estimates the smallest and largest eigenvalues of the graph's Laplacian with a Lanczos
+// iteration on the sparse Laplacian, for the cost of a few hundred sparse products at most.
+func (g *Graph) EstimateSpectrum(opts LanczosOptions) (SpectrumEstimate, error) {
+	if err := g.CheckSymmetric(); err != nil {
+		return SpectrumEstimate{}, err
+	}
+	lmin, lmax, diagnostics, err := g.SparseLaplacian().ExtremalEigenvalues(opts)
+	if err != nil {
+		return SpectrumEstimate{}, err
+	}
+	return SpectrumEstimate{LMin: lmin, LMax: lmax, LanczosDiagnostics: diagnostics}, nil
+}
+
+// CheckSymmetric returns an error if the graph's Laplacian is not symmetric, i.e. for directed graphs and for the
+// random-walk Laplacian. Lanczos and the Fourier basis are only defined for symmetric Laplacians.
+func (g *Graph) CheckSymmetric() error {
+	if g.directed {
+		return errors.New("the Laplacian of a directed graph is not symmetric")
+	}
+	if g.laplacian == RandomWalk {
+		return errors.New("the random-walk Laplacian is not symmetric, use the normalized Laplacian instead")
+	}
+	return nil
+}
+
+func randomVector(n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = rng.NormFloat64()
+	}
+	return v
+}
+
+// orthogonalize removes from v its components along the orthonormal vectors.
+func orthogonalize(v []float64, vectors [][]float64) {
+	for _, u := range vectors {
+		c := dotProduct(u, v)
+		for i := range v {
+			v[i] -= c * u[i]
+		}
+	}
+}
+
+func dotProduct(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func scale(v []float64, c float64) {
+	for i := range v {
+		v[i] *= c
+	}
+}
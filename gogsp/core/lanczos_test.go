@@ -0,0 +1,128 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+// paths returns count disjoint paths of n nodes each.
+func paths(count, n int) *Graph {
+	g := NewGraph()
+	for p := 0; p < count; p++ {
+		for i := 0; i+1 < n; i++ {
+			g.AddEdge(Node(p*n+i), Node(p*n+i+1), 1)
+		}
+	}
+	return g
+}
+
+// exactFunction returns f(L) x from the eigendecomposition of the graph's Laplacian.
+func exactFunction(t *testing.T, g *Graph, f func(float64) float64, x []float64) []float64 {
+	t.Helper()
+	b, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	coefficients, err := b.GraphFourierTransform(Signal(x))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, lambda := range b.Eigenvalues {
+		coefficients[k] *= f(lambda)
+	}
+	y, err := b.InverseGraphFourierTransform(coefficients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return y
+}
+
+func maxDifference(a, b []float64) float64 {
+	d := 0.0
+	for i := range a {
+		d = math.Max(d, math.Abs(a[i]-b[i]))
+	}
+	return d
+}
+
+// Two copies of a 12-node path have 12 distinct eigenvalues, so the Krylov space becomes invariant after 12 steps,
+// between two convergence checks.
+func TestLanczosInvariantExit(t *testing.T) {
+	g := paths(2, 12)
+	laplacian := g.SparseLaplacian()
+
+	lmin, lmax, diagnostics, err := laplacian.ExtremalEigenvalues(LanczosOptions{Tolerance: 1e-300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagnostics.Iterations != 12 || !diagnostics.Converged || diagnostics.Residual != 0 {
+		t.Fatalf("got %+v, want 12 iterations of an invariant subspace", diagnostics)
+	}
+	want := 2 - 2*math.Cos(11*math.Pi/12)
+	if math.Abs(lmin) > 1e-10 || math.Abs(lmax-want) > 1e-10 {
+		t.Errorf("got [%g, %g], want [0, %g]", lmin, lmax, want)
+	}
+
+	x := randomVector(24, 1)
+	f := func(lambda float64) float64 { return math.Exp(-lambda) }
+	y, diagnostics, err := laplacian.Function(f, x, LanczosOptions{Tolerance: 1e-300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagnostics.Iterations != 12 || !diagnostics.Converged {
+		t.Fatalf("got %+v, want 12 iterations of an invariant subspace", diagnostics)
+	}
+	if d := maxDifference(y, exactFunction(t, g, f, x)); d > 1e-10 {
+		t.Errorf("f(L) x is off by %g", d)
+	}
+}
+
+// With MaxIterations between two convergence checks, the estimates must come from the last tridiagonal matrix.
+func TestLanczosMaxIterationsExit(t *testing.T) {
+	g := paths(1, 40)
+	laplacian := g.SparseLaplacian()
+	opts := LanczosOptions{MaxIterations: 15, Tolerance: 1e-300}
+
+	lmin, lmax, diagnostics, err := laplacian.ExtremalEigenvalues(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagnostics.Iterations != 15 || diagnostics.Converged {
+		t.Fatalf("got %+v, want 15 iterations without convergence", diagnostics)
+	}
+	k, _, err := lanczos(laplacian.MulVec, randomVector(40, 0), nil, 15, func(*krylov) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	theta, _, residuals, err := k.ritz()
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := len(theta) - 1
+	if lmin != theta[0] || lmax != theta[last] || diagnostics.Residual != math.Max(residuals[0], residuals[last]) {
+		t.Errorf("got [%g, %g] within %g, want [%g, %g] within %g", lmin, lmax, diagnostics.Residual,
+			theta[0], theta[last], math.Max(residuals[0], residuals[last]))
+	}
+	estimate, err := g.EstimateSpectrum(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exact := 2 - 2*math.Cos(39*math.Pi/40); estimate.UpperBound() < exact {
+		t.Errorf("upper bound %g is below the largest eigenvalue %g", estimate.UpperBound(), exact)
+	}
+
+	// Small graphs cap MaxIterations to their size, here between two checks
+	small := paths(1, 15)
+	x := randomVector(15, 1)
+	f := func(lambda float64) float64 { return 1 / (1 + lambda) }
+	y, diagnostics, err := small.SparseLaplacian().Function(f, x, LanczosOptions{Tolerance: 1e-300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagnostics.Iterations != 15 {
+		t.Fatalf("got %d iterations, want 15", diagnostics.Iterations)
+	}
+	if d := maxDifference(y, exactFunction(t, small, f, x)); d > 1e-8 {
+		t.Errorf("f(L) x is off by %g", d)
+	}
+}
@@ -0,0 +1,113 @@
+// laplacian.go contains the Laplacian variants a Graph can be configured with.
+// Every Laplacian built from the graph (LaplacianMatrix, SparseLaplacian and the Fourier basis computed from them)
+// uses the kind set with the WithLaplacian option.
+
+package core
+
+import (
+	"math"
+)
+
+// LaplacianKind selects which Laplacian matrix is built from the weight matrix W and the degree matrix D.
+type LaplacianKind int
+
+const (
+	// Combinatorial is L = D - W
+	Combinatorial LaplacianKind = iota
+	// Normalized is the symmetric normalized Laplacian L = I - D^-1/2 W D^-1/2, with eigenvalues in [0, 2]
+	Normalized
+	// RandomWalk is L = I - D^-1 W. It is not symmetric, so it has no orthonormal Fourier basis
+	RandomWalk
+	// Signless is L = D + W
+	Signless
+)
+
+func (k LaplacianKind) String() string {
+	switch k {
+	case Combinatorial:
+		return "combinatorial"
+	case Normalized:
+		return "normalized"
+	case RandomWalk:
+		return "random-walk"
+	case Signless:
+		return "signless"
+	}
+	return "unknown"
+}
+
+// WithLaplacian sets the Laplacian kind of the graph. Graphs use the Combinatorial Laplacian by default.
+func WithLaplacian(kind LaplacianKind) GraphOption {
+	return func(g *Graph) {
+		g.laplacian = kind
+	}
+}
+
+// LaplacianKind returns the kind of Laplacian built from the graph.
+func (g *Graph) LaplacianKind() LaplacianKind {
+	return g.laplacian
+}
+
+// SetLaplacianKind changes the kind of Laplacian built from the graph, dropping any matrix built with the old one.
+func (g *Graph) SetLaplacianKind(kind LaplacianKind) {
+	if g.laplacian != kind {
+		g.laplacian = kind
+		g.invalidate()
+	}
+}
+
+// laplacianEntry returns entry (i, j) of the Laplacian from the weight w = W[i][j] and the degrees di and dj.
+// Self-loops never contribute to a Laplacian, so the diagonal only depends on the degree.
+// Isolated nodes (zero degree) get a zero row in the normalized and random-walk Laplacians instead of the
+// 1 of the identity, so that the multiplicity of the eigenvalue 0 is still the number of connected components.
+func (g *Graph) laplacianEntry(i, j int, w, di, dj float64) float64 {
+	switch g.laplacian {
+	case Normalized:
+		if di == 0 {
+			return 0
+		}
+		if i == j {
+			return 1
+		}
+		if dj == 0 {
+			return 0
+		}
+		return -w / math.Sqrt(di*dj)
+	case RandomWalk:
+		if di == 0 {
+			return 0
+		}
+		if i == j {
+			return 1
+		}
+		return -w / di
+	case Signless:
+		if i == j {
+			return di
+		}
+		return w
+	}
+	if i == j {
+		return di
+	}
+	return -w
+}
+
+// degreesOf returns the degree of every node from the dense weight matrix, ignoring self-loops. Directed graphs use
+// the degree given to the Directed option.
+func (g *Graph) degreesOf(weights [][]Weight) []float64 {
+	degrees := make([]float64, len(weights))
+	for i := range weights {
+		for j := range weights[i] {
+			if i == j {
+				continue
+			}
+			if g.directed && g.degree == InDegree {
+				degrees[j] += float64(weights[i][j])
+			} else {
+				degrees[i] += float64(weights[i][j])
+			}
+		}
+	}
+	return degrees
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func checkMatrix(t *testing.T, what string, got, want [][]float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(got[i][j]-want[i][j]) > 1e-12 {
+				t.Fatalf("%s: entry (%d, %d) is %g, want %g in\n%v", what, i, j, got[i][j], want[i][j], got)
+			}
+		}
+	}
+}
+
+// denseLaplacian rebuilds LaplacianMatrix and returns it as float64.
+func denseLaplacian(g *Graph) [][]float64 {
+	g.UpdateWeightedGraph()
+	g.UpdateLaplacianMatrix()
+	l := make([][]float64, len(g.LaplacianMatrix))
+	for i, row := range g.LaplacianMatrix {
+		l[i] = convertWeightToFloat64(row)
+	}
+	return l
+}
+
+func TestLaplacianKinds(t *testing.T) {
+	// The path 0 - 1 - 2 with weights 1 and 2, a self-loop on 2 that no Laplacian sees, and the isolated node 3:
+	// the degrees are 1, 3, 2 and 0
+	g := NewGraph()
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 2)
+	g.AddEdge(2, 2, 5)
+	g.AddNode(3)
+
+	s3, s6 := math.Sqrt(3), math.Sqrt(6)
+	tests := []struct {
+		kind LaplacianKind
+		want [][]float64
+	}{
+		{Combinatorial, [][]float64{{1, -1, 0, 0}, {-1, 3, -2, 0}, {0, -2, 2, 0}, {0, 0, 0, 0}}},
+		{Normalized, [][]float64{{1, -1 / s3, 0, 0}, {-1 / s3, 1, -2 / s6, 0}, {0, -2 / s6, 1, 0}, {0, 0, 0, 0}}},
+		{RandomWalk, [][]float64{{1, -1, 0, 0}, {-1. / 3, 1, -2. / 3, 0}, {0, -1, 1, 0}, {0, 0, 0, 0}}},
+		{Signless, [][]float64{{1, 1, 0, 0}, {1, 3, 2, 0}, {0, 2, 2, 0}, {0, 0, 0, 0}}},
+	}
+	for _, test := range tests {
+		g.SetLaplacianKind(test.kind)
+		if g.LaplacianKind() != test.kind {
+			t.Fatalf("got kind %v, want %v", g.LaplacianKind(), test.kind)
+		}
+		checkMatrix(t, test.kind.String()+" LaplacianMatrix", denseLaplacian(g), test.want)
+		checkMatrix(t, test.kind.String()+" SparseLaplacian", g.SparseLaplacian().Dense(), test.want)
+	}
+}
+
+func TestDirectedLaplacians(t *testing.T) {
+	tests := []struct {
+		degree DegreeKind
+		want   [][]float64
+	}{
+		// Out-degrees 3, 3 and 0 on the diagonal, every row sums to zero
+		{OutDegree, [][]float64{{3, -1, -2}, {0, 3, -3}, {0, 0, 0}}},
+		// In-degrees 0, 1 and 5 on the diagonal, every column sums to zero
+		{InDegree, [][]float64{{0, -1, -2}, {0, 1, -3}, {0, 0, 5}}},
+	}
+	for _, test := range tests {
+		g := NewGraph(Directed(test.degree))
+		g.AddEdge(0, 1, 1)
+		g.AddEdge(0, 2, 2)
+		g.AddEdge(1, 2, 3)
+		if g.Degree() != test.degree {
+			t.Fatalf("got degree %v, want %v", g.Degree(), test.degree)
+		}
+		dense := denseLaplacian(g)
+		checkMatrix(t, "LaplacianMatrix", dense, test.want)
+		checkMatrix(t, "SparseLaplacian", g.SparseLaplacian().Dense(), test.want)
+
+		for i := range dense {
+			row, column := 0.0, 0.0
+			for j := range dense {
+				row += dense[i][j]
+				column += dense[j][i]
+			}
+			if test.degree == OutDegree && row != 0 || test.degree == InDegree && column != 0 {
+				t.Errorf("degree %v: row %d sums to %g and column %d to %g", test.degree, i, row, i, column)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// matrix.go contains the matrix builders of a Graph.
+// matrix.go contains the following:
+// 	- UpdateWeightedGraph
+// 	- UpdateLaplacianMatrix
+// 	- UpdateAdjacencyMatrix
+// 	- LaplacianToMatDense and LaplacianToMatSymDense
+// 	- IsFullyConnected
+
+package core
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// UpdateWeightedGraph updates the weighted graph matrix based on the current graph's adjacency list.
+// Rows and columns follow the order of Nodes.
+func (g *Graph) UpdateWeightedGraph() {
+	g.WeightedGraph = g.denseFromAdjacencyList(true)
+}
+
+// denseFromAdjacencyList fills a dense matrix with the edge weights, indexed by Nodes, in O(N² + E).
+func (g *Graph) denseFromAdjacencyList(withSelfLoops bool) [][]Weight {
+	nodes := g.Nodes()
+	index := make(map[Node]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+	}
+	matrix := make([][]Weight, len(nodes))
+	for i, node := range nodes {
+		matrix[i] = make([]Weight, len(nodes))
+		for _, edge := range g.AdjacencyList[node] {
+			j := index[edge.Node]
+			if i != j || withSelfLoops {
+				matrix[i][j] = edge.Weight
+			}
+		}
+	}
+	return matrix
+}
+
+// UpdateLaplacianMatrix updates the Laplacian matrix based on the current graph's weighted graph.
+// The kind of Laplacian is set with the WithLaplacian option, and defaults to L = D - W.
+// For directed graphs D holds the in- or out-degrees, as chosen with the Directed option.
+func (g *Graph) UpdateLaplacianMatrix() {
+	size := len(g.WeightedGraph)
+	degrees := g.degreesOf(g.WeightedGraph)
+	g.LaplacianMatrix = make([][]Weight, size)
+	for i := 0; i < size; i++ {
+		g.LaplacianMatrix[i] = make([]Weight, size)
+		for j := 0; j < size; j++ {
+			w := float64(g.WeightedGraph[i][j])
+			g.LaplacianMatrix[i][j] = Weight(g.laplacianEntry(i, j, w, degrees[i], degrees[j]))
+		}
+	}
+}
+
+// UpdateAdjacencyMatrix updates the adjacency matrix based on the current graph's adjacency list.
+// Unlike the weighted graph, the adjacency matrix leaves out self-loops.
+func (g *Graph) UpdateAdjacencyMatrix() {
+	g.AdjacencyMatrix = g.denseFromAdjacencyList(false)
+}
+
+// IsFullyConnected reports whether every node can reach every other one: the graph is connected if undirected and
+// strongly connected if directed. The empty graph is considered fully connected.
+func (g *Graph) IsFullyConnected() bool {
+	return g.IsStronglyConnected()
+}
+
+func (g *Graph) LaplacianToMatDense() *mat.Dense {
+	r, c := len(g.LaplacianMatrix), len(g.LaplacianMatrix[0])
+	data := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		float64Row := convertWeightToFloat64(g.LaplacianMatrix[i])
+		copy(data[i*c:(i+1)*c], float64Row)
+	}
+	return mat.NewDense(r, c, data)
+}
+
+func convertWeightToFloat64(weights []Weight) []float64 {
+	float64s := make([]float64, len(weights))
+	for i, weight := range weights {
+		float64s[i] = float64(weight)
+	}
+	return float64s
+}
+
+// LaplacianToMatSymDense returns the Laplacian as a symmetric matrix for the symmetric eigensolver.
+// It refuses directed graphs and Laplacians that are not symmetric instead of silently using one triangle.
+func (g *Graph) LaplacianToMatSymDense() (*mat.SymDense, error) {
+	if g.directed {
+		return nil, fmt.Errorf("the Laplacian of a directed graph is not symmetric")
+	}
+	if g.laplacian == RandomWalk {
+		return nil, fmt.Errorf("the random-walk Laplacian is not symmetric, use the normalized Laplacian instead")
+	}
+	r := len(g.LaplacianMatrix)
+	data := make([]float64, r*r)
+	for i := 0; i < r; i++ {
+		for j := 0; j < i; j++ {
+			if g.LaplacianMatrix[i][j] != g.LaplacianMatrix[j][i] {
+				return nil, fmt.Errorf("the Laplacian is not symmetric at (%d, %d)", i, j)
+			}
+		}
+		float64Row := convertWeightToFloat64(g.LaplacianMatrix[i])
+		copy(data[i*r:(i+1)*r], float64Row)
+	}
+	return mat.NewSymDense(r, data), nil
+}
@@ -0,0 +1,253 @@
+// partial.go contains the partial eigendecomposition of a graph: its k lowest (or highest) graph frequencies.
+// Bandlimited reconstruction, spectral clustering and embeddings only use a few Fourier modes, which iterative
+// solvers compute from sparse products with the Laplacian at a fraction of the cost of the full Basis.
+// A PartialBasis also provides the graph Fourier transform restricted to the span of its modes.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Which selects the end of the spectrum a PartialBasis is computed for.
+type Which int
+
+const (
+	// Lowest selects the k smallest eigenvalues, the smoothest modes
+	Lowest Which = iota
+	// Highest selects the k largest eigenvalues, the most oscillating modes
+	Highest
+)
+
+func (w Which) String() string {
+	if w == Highest {
+		return "highest"
+	}
+	return "lowest"
+}
+
+// Solver selects the iterative eigensolver of a PartialBasis.
+type Solver int
+
+const (
+	// SolverShiftInvert runs Lanczos on (L + σI)^-1 for the lowest frequencies, which turns the clustered bottom of
+	// the spectrum into well separated eigenvalues. Every step solves a linear system by conjugate gradients.
+	// It falls back to SolverLanczos for the highest frequencies
+	SolverShiftInvert Solver = iota
+	// SolverLanczos runs Lanczos on L itself, which is cheaper per step but slower to converge at the bottom
+	SolverLanczos
+)
+
+// DefaultShift is σ for SolverShiftInvert as a fraction of lmax, when PartialOptions.Shift is zero.
+const DefaultShift = 1e-2
+
+// PartialOptions controls the computation of a PartialBasis.
+type PartialOptions struct {
+	Which  Which
+	Solver Solver
+	// Shift is σ > 0 for SolverShiftInvert. The smaller σ, the faster Lanczos converges, but the more each linear
+	// solve costs. If zero, DefaultShift times an estimate of lmax is used
+	Shift float64
+	LanczosOptions
+}
+
+// PartialBasis holds k eigenpairs of a graph's Laplacian, the modes as the columns of an N x k matrix.
+// For the combinatorial, normalized and signless Laplacians the modes are orthonormal. The random-walk Laplacian is
+// not symmetric, and its modes are orthonormal for the inner product weighted by the degrees, <x, y> = x^T D y,
+// which the transforms use as well.
+type PartialBasis struct {
+	// Eigenvalues holds the k graph frequencies, from the selected end of the spectrum inwards
+	Eigenvalues  []float64
+	Eigenvectors *mat.Dense
+	Which        Which
+	Laplacian    LaplacianKind
+	// Diagnostics reports the solver's work; its Residual is the largest ||L u - λ u|| of the returned pairs
+	Diagnostics LanczosDiagnostics
+	// degrees weights the inner product for the random-walk Laplacian, nil otherwise
+	degrees []float64
+}
+
+// PartialBasis computes k eigenpairs of the graph's Laplacian, of whichever kind it is, with an iterative solver on
+// the sparse Laplacian. Unlike Basis the result is not cached. Directed graphs are not supported.
+func (g *Graph) PartialBasis(k int, opts PartialOptions) (*PartialBasis, error) {
+	if g.directed {
+		return nil, errors.New("the Laplacian of a directed graph is not symmetric")
+	}
+	n := len(g.Nodes())
+	if n == 0 {
+		return nil, errors.New("cannot factorize the Laplacian of an empty graph")
+	}
+	if k < 1 || k > n {
+		return nil, fmt.Errorf("cannot compute %d eigenpairs of a graph with %d nodes", k, n)
+	}
+
+	laplacian := g.SparseLaplacian()
+	apply := operator(laplacian.MulVec)
+	var degrees, sqrtDegrees []float64
+	if g.laplacian == RandomWalk {
+		// D^1/2 L_rw D^-1/2 is the normalized Laplacian, whose eigenvectors u give the modes D^-1/2 u
+		w := g.SparseWeights()
+		degrees = make([]float64, n)
+		sqrtDegrees = make([]float64, n)
+		for i := range degrees {
+			for j := w.RowPtr[i]; j < w.RowPtr[i+1]; j++ {
+				if w.ColInd[j] != i {
+					degrees[i] += w.Values[j]
+				}
+			}
+		}
+		for i, d := range degrees {
+			if d <= 0 {
+				return nil, fmt.Errorf("node %v has no edges, the random-walk modes are undefined", g.order[i])
+			}
+			sqrtDegrees[i] = math.Sqrt(d)
+		}
+		apply = func(v []float64) []float64 {
+			u := make([]float64, n)
+			for i := range u {
+				u[i] = v[i] / sqrtDegrees[i]
+			}
+			y := laplacian.MulVec(u)
+			for i := range y {
+				y[i] *= sqrtDegrees[i]
+			}
+			return y
+		}
+	}
+
+	values, vectors, diagnostics, err := partialEigenpairs(apply, n, k, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the true residuals, which do not depend on the solver
+	diagnostics.Residual = 0
+	for j, lambda := range values {
+		u := mat.Col(nil, j, vectors)
+		r := apply(u)
+		norm := 0.0
+		for i := range r {
+			norm += (r[i] - lambda*u[i]) * (r[i] - lambda*u[i])
+		}
+		diagnostics.Residual = math.Max(diagnostics.Residual, math.Sqrt(norm))
+		if sqrtDegrees != nil {
+			for i := range u {
+				u[i] /= sqrtDegrees[i]
+			}
+			vectors.SetCol(j, u)
+		}
+	}
+
+	return &PartialBasis{
+		Eigenvalues:  values,
+		Eigenvectors: vectors,
+		Which:        opts.Which,
+		Laplacian:    g.laplacian,
+		Diagnostics:  diagnostics,
+		degrees:      degrees,
+	}, nil
+}
+
+// partialEigenpairs runs the solver selected by opts on a symmetric positive semi-definite operator.
+func partialEigenpairs(apply operator, n, k int, opts PartialOptions) ([]float64, *mat.Dense, LanczosDiagnostics, error) {
+	if opts.Which == Highest || opts.Solver == SolverLanczos {
+		return eigenpairs(apply, n, k, opts.Which == Highest, opts.LanczosOptions)
+	}
+
+	shift := opts.Shift
+	if shift <= 0 {
+		_, lmax, _, err := extremalEigenvalues(apply, n, LanczosOptions{Tolerance: 1e-3, Seed: opts.Seed})
+		if err != nil {
+			return nil, nil, LanczosDiagnostics{}, err
+		}
+		shift = DefaultShift * lmax
+		if shift <= 0 {
+			// L = 0, every vector is a mode
+			shift = 1
+		}
+	}
+
+	// The largest eigenvalues μ of (L + σI)^-1 are 1 / (λ + σ) for the smallest λ
+	var failure error
+	products := 0
+	inverse := func(v []float64) []float64 {
+		x, iterations, err := solveShifted(apply, shift, v, 1e-12, 10*n+100)
+		products += iterations
+		if err != nil && failure == nil {
+			failure = err
+		}
+		return x
+	}
+	mu, vectors, diagnostics, err := eigenpairs(inverse, n, k, true, opts.LanczosOptions)
+	if err != nil {
+		return nil, nil, diagnostics, err
+	}
+	if failure != nil {
+		return nil, nil, diagnostics, failure
+	}
+	diagnostics.Iterations += products
+	values := make([]float64, len(mu))
+	for i := range mu {
+		values[i] = math.Max(0, 1/mu[i]-shift)
+	}
+	return values, vectors, diagnostics, nil
+}
+
+// solveShifted solves (A + σI) x = b by conjugate gradients and returns x with the number of products with A.
+func solveShifted(apply operator, shift float64, b []float64, tolerance float64, maxIterations int) ([]float64, int, error) {
+	return ConjugateGradient(func(v []float64) ([]float64, error) {
+		av := apply(v)
+		for i := range av {
+			av[i] += shift * v[i]
+		}
+		return av, nil
+	}, b, tolerance, maxIterations)
+}
+
+// Dims returns the number of nodes and the number of modes of the basis.
+func (p *PartialBasis) Dims() (n, k int) {
+	return p.Eigenvectors.Dims()
+}
+
+// GraphFourierTransform returns the k coefficients of the signal on the modes of the basis, U^T x, or U^T D x for
+// the random-walk Laplacian. They are the coefficients of the projection of the signal onto the span of the modes.
+func (p *PartialBasis) GraphFourierTransform(s Signal) (Signal, error) {
+	n, k := p.Dims()
+	if len(s) != n {
+		return nil, &SizeMismatchError{What: "signal", Got: len(s), Of: "basis", Want: n}
+	}
+	x := s
+	if p.degrees != nil {
+		x = make(Signal, n)
+		for i := range x {
+			x[i] = p.degrees[i] * s[i]
+		}
+	}
+	coefficients := mat.NewVecDense(k, nil)
+	coefficients.MulVec(p.Eigenvectors.T(), mat.NewVecDense(n, x))
+	return Signal(coefficients.RawVector().Data), nil
+}
+
+// InverseGraphFourierTransform returns the bandlimited signal U c with the given k coefficients.
+func (p *PartialBasis) InverseGraphFourierTransform(c Signal) (Signal, error) {
+	n, k := p.Dims()
+	if len(c) != k {
+		return nil, &SizeMismatchError{What: "coefficients", Got: len(c), Of: "basis", Want: k}
+	}
+	signal := mat.NewVecDense(n, nil)
+	signal.MulVec(p.Eigenvectors, mat.NewVecDense(k, c))
+	return Signal(signal.RawVector().Data), nil
+}
+
+// Project returns the component of the signal in the span of the modes, the inverse transform of its transform.
+func (p *PartialBasis) Project(s Signal) (Signal, error) {
+	c, err := p.GraphFourierTransform(s)
+	if err != nil {
+		return nil, err
+	}
+	return p.InverseGraphFourierTransform(c)
+}
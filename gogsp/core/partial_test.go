@@ -0,0 +1,147 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// checkModes checks that the k columns of got match those of want up to sign.
+func checkModes(t *testing.T, want, got *mat.Dense, k int, tolerance float64) {
+	t.Helper()
+	for j := 0; j < k; j++ {
+		w, g := mat.Col(nil, j, want), mat.Col(nil, j, got)
+		if dotProduct(w, g) < 0 {
+			scale(g, -1)
+		}
+		if d := maxDifference(w, g); d > tolerance {
+			t.Errorf("mode %d is off by %g", j, d)
+		}
+	}
+}
+
+func TestPartialBasisLowest(t *testing.T) {
+	g := paths(1, 30)
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, solver := range []Solver{SolverShiftInvert, SolverLanczos} {
+		partial, err := g.PartialBasis(5, PartialOptions{Solver: solver, LanczosOptions: LanczosOptions{Tolerance: 1e-12}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, lambda := range partial.Eigenvalues {
+			if math.Abs(lambda-basis.Eigenvalues[k]) > 1e-10 {
+				t.Errorf("solver %d: eigenvalue %d is %g, want %g", solver, k, lambda, basis.Eigenvalues[k])
+			}
+		}
+		checkModes(t, basis.Eigenvectors, partial.Eigenvectors, 5, 1e-6)
+		if partial.Diagnostics.Residual > 1e-8 {
+			t.Errorf("solver %d: residual %g", solver, partial.Diagnostics.Residual)
+		}
+	}
+
+	partial, err := g.PartialBasis(3, PartialOptions{Which: Highest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, lambda := range partial.Eigenvalues {
+		if want := basis.Eigenvalues[29-k]; math.Abs(lambda-want) > 1e-8 {
+			t.Errorf("highest eigenvalue %d is %g, want %g", k, lambda, want)
+		}
+	}
+}
+
+func TestPartialBasisRandomWalk(t *testing.T) {
+	// The random-walk Laplacian has the eigenvalues of the normalized one
+	g := paths(1, 30)
+	g.SetLaplacianKind(Normalized)
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetLaplacianKind(RandomWalk)
+	partial, err := g.PartialBasis(4, PartialOptions{LanczosOptions: LanczosOptions{Tolerance: 1e-12}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	laplacian := g.SparseLaplacian()
+	degrees := partial.degrees
+	for k, lambda := range partial.Eigenvalues {
+		if math.Abs(lambda-basis.Eigenvalues[k]) > 1e-10 {
+			t.Errorf("eigenvalue %d is %g, want %g", k, lambda, basis.Eigenvalues[k])
+		}
+		u := mat.Col(nil, k, partial.Eigenvectors)
+		lu := laplacian.MulVec(u)
+		for i := range lu {
+			lu[i] -= lambda * u[i]
+		}
+		if r := math.Sqrt(dotProduct(lu, lu)); r > 1e-8 {
+			t.Errorf("mode %d has residual %g", k, r)
+		}
+		// The modes are orthonormal for the inner product weighted by the degrees
+		for l := 0; l <= k; l++ {
+			v := mat.Col(nil, l, partial.Eigenvectors)
+			product := 0.0
+			for i := range u {
+				product += degrees[i] * u[i] * v[i]
+			}
+			want := 0.0
+			if k == l {
+				want = 1
+			}
+			if math.Abs(product-want) > 1e-8 {
+				t.Errorf("<u_%d, u_%d>_D is %g, want %g", k, l, product, want)
+			}
+		}
+	}
+}
+
+func TestPartialBasisProject(t *testing.T) {
+	g := paths(1, 30)
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	partial, err := g.PartialBasis(6, PartialOptions{LanczosOptions: LanczosOptions{Tolerance: 1e-12}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A signal in the span of the 6 lowest modes is left alone, and the rest of the spectrum is removed
+	band := make(Signal, 30)
+	noisy := make(Signal, 30)
+	for k := 0; k < 30; k++ {
+		u := mat.Col(nil, k, basis.Eigenvectors)
+		for i := range band {
+			if k < 6 {
+				band[i] += float64(k+1) * u[i]
+			}
+			noisy[i] += float64(k+1) * u[i]
+		}
+	}
+	for _, x := range []Signal{band, noisy} {
+		projected, err := partial.Project(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := maxDifference(projected, band); d > 1e-6 {
+			t.Errorf("the projection is off by %g", d)
+		}
+	}
+
+	coefficients, err := partial.GraphFourierTransform(band)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, c := range coefficients {
+		if math.Abs(math.Abs(c)-float64(k+1)) > 1e-6 {
+			t.Errorf("coefficient %d is %g, want ±%d", k, c, k+1)
+		}
+	}
+	if _, err := partial.InverseGraphFourierTransform(make(Signal, 5)); err == nil {
+		t.Error("5 coefficients are accepted by a basis of 6 modes")
+	}
+}
@@ -0,0 +1,159 @@
+// signal.go contains the Signal type and utility functions for working with signals.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Signal represents a signal over the nodes of a graph, one value per node in matrix order. It does not know its
+// graph; GraphSignal binds the values to one and keeps them aligned when nodes are added or removed.
+type Signal []float64
+
+// SizeMismatchError is returned when a signal, or a vector or matrix like one, does not have the size its graph,
+// basis or filter expects.
+type SizeMismatchError struct {
+	// What names the mismatched value, e.g. "signal" or "coefficients"
+	What string
+	// Got is its number of values
+	Got int
+	// Of names what fixes the expected size, e.g. "graph" or "basis"
+	Of string
+	// Want is the number of values expected
+	Want int
+}
+
+func (e *SizeMismatchError) Error() string {
+	return fmt.Sprintf("%s has %d values but the %s expects %d", e.What, e.Got, e.Of, e.Want)
+}
+
+// ErrGraphMismatch is returned when a GraphSignal is used with a graph other than its own.
+var ErrGraphMismatch = errors.New("the signal belongs to another graph")
+
+// CheckSize returns a SizeMismatchError unless the signal has one value per node of the graph.
+func (s Signal) CheckSize(g *Graph) error {
+	if n := len(g.AdjacencyList); len(s) != n {
+		return &SizeMismatchError{What: "signal", Got: len(s), Of: "graph", Want: n}
+	}
+	return nil
+}
+
+// CreateSignal generates a new signal with the given size
+func CreateSignal(size int) Signal {
+	return make(Signal, size)
+}
+
+// Set sets the value of the signal at a specific matrix index, which is the node itself only for graphs whose nodes
+// were added as 0..N-1 in order. It returns an error out of range. Use SetAt to address a node by its ID.
+func (s Signal) Set(n Node, value float64) error {
+	if err := s.checkIndex(n); err != nil {
+		return err
+	}
+	s[n] = value
+	return nil
+}
+
+// SetSignal copies the values, in matrix order, into the signal. Both must have one value per node of the graph.
+func (s Signal) SetSignal(g *Graph, arr []float64) error {
+	if err := s.CheckSize(g); err != nil {
+		return err
+	}
+	if len(arr) != len(s) {
+		return &SizeMismatchError{What: "array", Got: len(arr), Of: "graph", Want: len(s)}
+	}
+	copy(s, arr)
+	return nil
+}
+
+// Get returns the value of the signal at a specific matrix index, see Set. Use At to address a node by its ID.
+func (s Signal) Get(n Node) (float64, error) {
+	if err := s.checkIndex(n); err != nil {
+		return 0, err
+	}
+	return s[n], nil
+}
+
+func (s Signal) checkIndex(n Node) error {
+	if n < 0 || int(n) >= len(s) {
+		return fmt.Errorf("index %d out of range for a signal of %d values", n, len(s))
+	}
+	return nil
+}
+
+// At returns the value of the signal at the node of g, looked up through the node's matrix index.
+func (s Signal) At(g *Graph, n Node) (float64, error) {
+	i, err := s.indexOf(g, n)
+	if err != nil {
+		return 0, err
+	}
+	return s[i], nil
+}
+
+// SetAt sets the value of the signal at the node of g, looked up through the node's matrix index.
+func (s Signal) SetAt(g *Graph, n Node, value float64) error {
+	i, err := s.indexOf(g, n)
+	if err != nil {
+		return err
+	}
+	s[i] = value
+	return nil
+}
+
+// AtLabel returns the value of the signal at the node of g with the given label, see Graph.NodeByLabel.
+func (s Signal) AtLabel(g *Graph, label string) (float64, error) {
+	n, ok := g.NodeByLabel(label)
+	if !ok {
+		return 0, fmt.Errorf("no node labelled %q in the graph", label)
+	}
+	return s.At(g, n)
+}
+
+func (s Signal) indexOf(g *Graph, n Node) (int, error) {
+	i, ok := g.NodeIndex(n)
+	if !ok {
+		return -1, fmt.Errorf("node %v is not in the graph", n)
+	}
+	if i >= len(s) {
+		return -1, &SizeMismatchError{What: "signal", Got: len(s), Of: "graph", Want: len(g.AdjacencyList)}
+	}
+	return i, nil
+}
+
+// Delete returns the signal without the value at the given index, e.g. the index returned by Graph.RemoveNode.
+// The values after the index are shifted down, matching the new node order of the graph.
+func (s Signal) Delete(index int) Signal {
+	return append(s[:index:index], s[index+1:]...)
+}
+
+// Mean calculates the mean value of the signal
+func (s Signal) Mean() float64 {
+	total := 0.0
+	for _, value := range s {
+		total += value
+	}
+	return total / float64(len(s))
+}
+
+// Normalize normalizes the signal to have a mean of 0 and standard deviation of 1
+func (s Signal) Normalize() {
+	mean := s.Mean()
+	variance := 0.0
+	for _, value := range s {
+		diff := value - mean
+		variance += diff * diff
+	}
+	variance = variance / float64(len(s))
+	stdDev := math.Sqrt(variance)
+
+	for i := range s {
+		s[i] = (s[i] - mean) / stdDev
+	}
+}
+
+// PrintSignal prints the signal in vector format
+func (s Signal) PrintSignal() {
+	fmt.Printf("%.4f", s)
+	fmt.Print("\n")
+}
@@ -0,0 +1,69 @@
+package core
+
+import "testing"
+
+// denseProduct returns a x for a dense matrix.
+func denseProduct(a [][]float64, x []float64) []float64 {
+	y := make([]float64, len(a))
+	for i := range a {
+		for j := range a[i] {
+			y[i] += a[i][j] * x[j]
+		}
+	}
+	return y
+}
+
+// transpose returns the transpose of a dense matrix.
+func transpose(a [][]float64) [][]float64 {
+	t := make([][]float64, len(a[0]))
+	for j := range t {
+		t[j] = make([]float64, len(a))
+		for i := range a {
+			t[j][i] = a[i][j]
+		}
+	}
+	return t
+}
+
+func TestCSRProducts(t *testing.T) {
+	// A directed graph with a self-loop, so that W is neither symmetric nor free of diagonal entries
+	g := NewGraph(Directed(OutDegree))
+	for i := 0; i < 4; i++ {
+		g.AddNode(Node(i))
+	}
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(0, 3, 2)
+	g.AddEdge(2, 0, 3)
+	g.AddEdge(3, 3, 4)
+	g.AddEdge(3, 2, 5)
+	w := g.SparseWeights()
+	want := [][]float64{{0, 1, 0, 2}, {0, 0, 0, 0}, {3, 0, 0, 0}, {0, 0, 5, 4}}
+	checkMatrix(t, "SparseWeights", w.Dense(), want)
+	if r, c := w.Dims(); r != 4 || c != 4 || w.NNZ() != 5 {
+		t.Errorf("got a %dx%d matrix with %d entries, want 4x4 with 5", r, c, w.NNZ())
+	}
+	if w.At(3, 2) != 5 || w.At(2, 3) != 0 {
+		t.Errorf("got W[3][2] = %g and W[2][3] = %g, want 5 and 0", w.At(3, 2), w.At(2, 3))
+	}
+
+	x := []float64{1, -2, 3, 0.5}
+	for _, m := range []*CSR{w, g.SparseLaplacian()} {
+		dense := m.Dense()
+		if d := maxDifference(m.MulVec(x), denseProduct(dense, x)); d > 1e-12 {
+			t.Errorf("MulVec is off by %g", d)
+		}
+		if d := maxDifference(m.MulVecTrans(x), denseProduct(transpose(dense), x)); d > 1e-12 {
+			t.Errorf("MulVecTrans is off by %g", d)
+		}
+		checkMatrix(t, "T", m.T().Dense(), transpose(dense))
+		if d := maxDifference(m.T().MulVec(x), m.MulVecTrans(x)); d > 1e-12 {
+			t.Errorf("T().MulVec and MulVecTrans differ by %g", d)
+		}
+	}
+
+	// The cached matrices follow the graph
+	g.AddEdge(1, 2, 6)
+	if g.SparseWeights().At(1, 2) != 6 || g.SparseLaplacian().At(1, 1) != 6 {
+		t.Errorf("the sparse matrices were not rebuilt after AddEdge")
+	}
+}
@@ -0,0 +1,146 @@
+// spectral.go contains the spectral basis of a Graph and the graph Fourier transform built on it.
+// The eigendecomposition of the Laplacian is computed once and cached on the graph as a Basis, so that
+// transforming or filtering many signals on the same graph only pays for a single factorization.
+
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Basis is the Fourier basis of a graph: the eigendecomposition L = U Λ U^T of its Laplacian.
+// A Basis is a snapshot; it is not updated when the graph it was computed from changes.
+type Basis struct {
+	// Eigenvalues holds the graph frequencies in ascending order
+	Eigenvalues []float64
+	// Eigenvectors holds the Fourier modes as columns, Eigenvectors.ColView(k) belongs to Eigenvalues[k]
+	Eigenvectors *mat.Dense
+	// LMax is the largest eigenvalue of the Laplacian
+	LMax float64
+	// Coherence is the largest absolute entry of Eigenvectors, i.e. how localized the most localized mode is
+	Coherence float64
+}
+
+// NewBasis computes the Fourier basis of g from scratch.
+// The weighted graph and Laplacian matrices are rebuilt from the adjacency list first.
+func NewBasis(g *Graph) (*Basis, error) {
+	g.UpdateWeightedGraph()
+	g.UpdateLaplacianMatrix()
+	if len(g.LaplacianMatrix) == 0 {
+		return nil, fmt.Errorf("cannot factorize the Laplacian of an empty graph")
+	}
+
+	// Convert g.LaplacianMatrix to *mat.SymDense, this fails for directed graphs
+	l, err := g.LaplacianToMatSymDense()
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare EigenSym and compute the eigendecomposition of the Laplacian
+	var es mat.EigenSym
+	ok := es.Factorize(l, true)
+	if !ok {
+		return nil, fmt.Errorf("failed to factorize Laplacian matrix")
+	}
+
+	// Get eigen vectors
+	eigenVectors := new(mat.Dense)
+	es.VectorsTo(eigenVectors)
+
+	// EigenSym returns the eigenvalues in ascending order already
+	eigenValues := es.Values(nil)
+
+	coherence := 0.0
+	r, c := eigenVectors.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			coherence = math.Max(coherence, math.Abs(eigenVectors.At(i, j)))
+		}
+	}
+
+	return &Basis{
+		Eigenvalues:  eigenValues,
+		Eigenvectors: eigenVectors,
+		LMax:         eigenValues[len(eigenValues)-1],
+		Coherence:    coherence,
+	}, nil
+}
+
+// Basis returns the Fourier basis of the graph, computing it on first use.
+// The cached basis is dropped whenever the graph is mutated through its Add, Remove or Set methods.
+// Code that edits AdjacencyList directly must call InvalidateBasis itself.
+func (g *Graph) Basis() (*Basis, error) {
+	if g.basis == nil {
+		b, err := NewBasis(g)
+		if err != nil {
+			return nil, err
+		}
+		g.basis = b
+	}
+	return g.basis, nil
+}
+
+// InvalidateBasis drops the cached Fourier basis so that the next call to Basis recomputes it.
+func (g *Graph) InvalidateBasis() {
+	g.basis = nil
+}
+
+// Size returns the number of nodes (and graph frequencies) the basis was computed for.
+func (b *Basis) Size() int {
+	return len(b.Eigenvalues)
+}
+
+// GraphFourierTransform projects the signal onto the Fourier modes of the basis.
+func (b *Basis) GraphFourierTransform(s Signal) (Signal, error) {
+	if len(s) != b.Size() {
+		return nil, &SizeMismatchError{What: "signal", Got: len(s), Of: "basis", Want: b.Size()}
+	}
+
+	// Convert signal to mat.VecDense
+	sVec := mat.NewVecDense(len(s), s)
+
+	// Transformed signal as a vector
+	var tsVec mat.VecDense
+	tsVec.MulVec(b.Eigenvectors.T(), sVec)
+
+	return Signal(tsVec.RawVector().Data), nil
+}
+
+// InverseGraphFourierTransform maps spectral coefficients back to the vertex domain.
+func (b *Basis) InverseGraphFourierTransform(s Signal) (Signal, error) {
+	if len(s) != b.Size() {
+		return nil, &SizeMismatchError{What: "signal", Got: len(s), Of: "basis", Want: b.Size()}
+	}
+
+	// Convert signal to mat.VecDense
+	sVec := mat.NewVecDense(len(s), s)
+
+	// Inverse transformed signal as a vector
+	var itsVec mat.VecDense
+
+	// Now, you use eigenVectors (not Transposed).
+	itsVec.MulVec(b.Eigenvectors, sVec)
+
+	return Signal(itsVec.RawVector().Data), nil
+}
+
+// GraphFourierTransform projects the signal onto the eigenvectors of the graph's Laplacian.
+func (g *Graph) GraphFourierTransform(s Signal) (Signal, error) {
+	b, err := g.Basis()
+	if err != nil {
+		return nil, err
+	}
+	return b.GraphFourierTransform(s)
+}
+
+// InverseGraphFourierTransform maps spectral coefficients back to the vertex domain of the graph.
+func (g *Graph) InverseGraphFourierTransform(s Signal) (Signal, error) {
+	b, err := g.Basis()
+	if err != nil {
+		return nil, err
+	}
+	return b.InverseGraphFourierTransform(s)
+}
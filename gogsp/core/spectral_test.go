@@ -0,0 +1,99 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func checkEigenvalues(t *testing.T, what string, b *Basis, want []float64) {
+	t.Helper()
+	if d := maxDifference(b.Eigenvalues, want); len(b.Eigenvalues) != len(want) || d > 1e-12 {
+		t.Fatalf("%s: got eigenvalues %v, want %v", what, b.Eigenvalues, want)
+	}
+	if math.Abs(b.LMax-want[len(want)-1]) > 1e-12 {
+		t.Errorf("%s: got lmax %g, want %g", what, b.LMax, want[len(want)-1])
+	}
+}
+
+func TestBasisCache(t *testing.T) {
+	// The path 0 - 1 - 2 has the eigenvalues 0, 1 and 3
+	g := paths(1, 3)
+	b, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEigenvalues(t, "path", b, []float64{0, 1, 3})
+	if again, _ := g.Basis(); again != b {
+		t.Error("the basis is computed again on an unchanged graph")
+	}
+
+	// Every mutation drops the cached basis, and the next one matches the new graph
+	steps := []struct {
+		name   string
+		mutate func()
+		want   []float64
+	}{
+		// The triangle has the eigenvalues 0, 3 and 3
+		{"AddEdge", func() { g.AddEdge(0, 2, 1) }, []float64{0, 3, 3}},
+		// L = [[3, -1, -2], [-1, 2, -1], [-2, -1, 3]] has the eigenvalues 0, 3 and 5
+		{"SetEdgeWeight", func() { g.SetEdgeWeight(0, 2, 2) }, []float64{0, 3, 5}},
+		// The edge 0 - 1 of weight 1 is left, with the eigenvalues 0 and 2
+		{"RemoveNode", func() { g.RemoveNode(2) }, []float64{0, 2}},
+		{"SetLaplacianKind", func() { g.SetLaplacianKind(Signless) }, []float64{0, 2}},
+		{"RemoveEdge", func() { g.RemoveEdge(0, 1) }, []float64{0, 0}},
+	}
+	for _, step := range steps {
+		step.mutate()
+		next, err := g.Basis()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if next == b {
+			t.Fatalf("%s keeps the cached basis", step.name)
+		}
+		checkEigenvalues(t, step.name, next, step.want)
+		b = next
+	}
+
+	// Editing AdjacencyList directly needs InvalidateBasis
+	g.AdjacencyList[0] = append(g.AdjacencyList[0], Edge{1, 1})
+	g.AdjacencyList[1] = append(g.AdjacencyList[1], Edge{0, 1})
+	g.InvalidateBasis()
+	if next, _ := g.Basis(); next == b || next.LMax != 2 {
+		t.Errorf("InvalidateBasis keeps the basis, or the new one has lmax %g", next.LMax)
+	}
+
+	if _, err := NewGraph().Basis(); err == nil {
+		t.Error("the empty graph has a basis")
+	}
+	if _, err := NewGraph(Directed(OutDegree)).Basis(); err == nil {
+		t.Error("a directed graph has a basis")
+	}
+}
+
+func TestGraphFourierTransform(t *testing.T) {
+	g := paths(1, 5)
+	x := randomVector(5, 1)
+	spectrum, err := g.GraphFourierTransform(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The constant mode 1/sqrt(5) carries the mean, up to its sign
+	mean := 0.0
+	for _, v := range x {
+		mean += v / math.Sqrt(5)
+	}
+	if math.Abs(math.Abs(spectrum[0])-math.Abs(mean)) > 1e-12 {
+		t.Errorf("the first coefficient is %g, want ±%g", spectrum[0], mean)
+	}
+	back, err := g.InverseGraphFourierTransform(spectrum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := maxDifference(back, x); d > 1e-12 {
+		t.Errorf("the inverse transform is off by %g", d)
+	}
+	if _, err := g.GraphFourierTransform(x[:4]); err == nil {
+		t.Error("a signal of 4 values is transformed on 5 nodes")
+	}
+}
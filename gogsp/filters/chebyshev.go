@@ -0,0 +1,187 @@
+// chebyshev.go contains the Chebyshev polynomial approximation of spectral filters.
+// A kernel g(λ) on [0, lmax] is approximated by a truncated Chebyshev series p(λ) = c_0/2 + sum_k c_k T_k(λ),
+// and p(L) x is computed with the three-term recurrence of the Chebyshev polynomials. Applying an order K
+// approximation costs K products with the sparse Laplacian and no eigendecomposition at all.
+// See Hammond, Vandergheynst and Gribonval, "Wavelets on graphs via spectral graph theory", 2011.
+
+package filters
+
+import (
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"fmt"
+	"math"
+)
+
+// Kernel is the frequency response g(λ) of a spectral filter, evaluated on Laplacian eigenvalues.
+type Kernel func(lambda float64) float64
+
+// Chebyshev is a Chebyshev polynomial approximation of a kernel on the interval [0, LMax].
+type Chebyshev struct {
+	// Coefficients holds c_0 ... c_K, the order of the approximation is K = len(Coefficients)-1
+	Coefficients []float64
+	// LMax is the upper end of the approximation interval, it must bound the largest Laplacian eigenvalue
+	LMax float64
+}
+
+// NewChebyshev computes the order+1 Chebyshev coefficients of the kernel on [0, lmax] by Chebyshev-Gauss quadrature.
+func NewChebyshev(kernel Kernel, lmax float64, order int) (*Chebyshev, error) {
+	if order < 0 {
+		return nil, fmt.Errorf("chebyshev order must be non-negative, got %d", order)
+	}
+	if lmax <= 0 {
+		return nil, fmt.Errorf("chebyshev interval must have a positive lmax, got %g", lmax)
+	}
+
+	// Quadrature on order+1 Chebyshev nodes, mapped from [-1, 1] to [0, lmax]
+	n := order + 1
+	halfWidth := lmax / 2
+	samples := make([]float64, n)
+	for j := 0; j < n; j++ {
+		theta := math.Pi * (float64(j) + 0.5) / float64(n)
+		samples[j] = kernel(halfWidth*math.Cos(theta) + halfWidth)
+	}
+
+	coefficients := make([]float64, n)
+	for k := 0; k < n; k++ {
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			theta := math.Pi * (float64(j) + 0.5) / float64(n)
+			sum += samples[j] * math.Cos(float64(k)*theta)
+		}
+		coefficients[k] = 2 * sum / float64(n)
+	}
+
+	return &Chebyshev{Coefficients: coefficients, LMax: lmax}, nil
+}
+
+// Order returns the degree of the approximating polynomial.
+func (c *Chebyshev) Order() int {
+	return len(c.Coefficients) - 1
+}
+
+// Evaluate returns the value of the approximating polynomial at λ, using Clenshaw's recurrence.
+func (c *Chebyshev) Evaluate(lambda float64) float64 {
+	if len(c.Coefficients) == 0 {
+		return 0
+	}
+	halfWidth := c.LMax / 2
+	t := (lambda - halfWidth) / halfWidth
+	b1, b2 := 0.0, 0.0
+	for k := len(c.Coefficients) - 1; k >= 1; k-- {
+		b1, b2 = 2*t*b1-b2+c.Coefficients[k], b1
+	}
+	return t*b1 - b2 + c.Coefficients[0]/2
+}
+
+// Apply computes p(L) x on the sparse Laplacian of the graph with Order() sparse matrix-vector products.
+func (c *Chebyshev) Apply(graph *graphs.Graph, signal signals.Signal) (signals.Signal, error) {
+	return c.apply(graph.SparseLaplacian(), signal)
+}
+
+func (c *Chebyshev) apply(laplacian *core.CSR, signal signals.Signal) (signals.Signal, error) {
+	if laplacian.Rows != len(signal) {
+		return nil, &core.SizeMismatchError{What: "signal", Got: len(signal), Of: "graph", Want: laplacian.Rows}
+	}
+	output := make(signals.Signal, len(signal))
+	if len(c.Coefficients) == 0 {
+		return output, nil
+	}
+
+	// shifted returns (L - a I) v / a with a = lmax/2, which maps the spectrum of L onto [-1, 1]
+	halfWidth := c.LMax / 2
+	shifted := func(v []float64) []float64 {
+		lv := laplacian.MulVec(v)
+		for i := range lv {
+			lv[i] = (lv[i] - halfWidth*v[i]) / halfWidth
+		}
+		return lv
+	}
+
+	// T_0 x = x and T_1 x = shifted(x)
+	previous := make([]float64, len(signal))
+	copy(previous, signal)
+	for i := range output {
+		output[i] = c.Coefficients[0] / 2 * previous[i]
+	}
+	if len(c.Coefficients) == 1 {
+		return output, nil
+	}
+	current := shifted(previous)
+	for i := range output {
+		output[i] += c.Coefficients[1] * current[i]
+	}
+
+	// T_k x = 2 shifted(T_{k-1} x) - T_{k-2} x
+	for k := 2; k < len(c.Coefficients); k++ {
+		next := shifted(current)
+		for i := range next {
+			next[i] = 2*next[i] - previous[i]
+			output[i] += c.Coefficients[k] * next[i]
+		}
+		previous, current = current, next
+	}
+	return output, nil
+}
+
+// ApproximationError compares a Chebyshev approximation with the exact spectral filter it approximates.
+type ApproximationError struct {
+	// Spectral is max |g(λ) - p(λ)| over the Laplacian eigenvalues, the operator 2-norm of g(L) - p(L)
+	Spectral float64
+	// Signal is ||g(L) x - p(L) x|| / ||g(L) x|| for the signal the comparison was made with
+	Signal float64
+}
+
+// CompareExact filters the signal both exactly, in the eigenbasis of the graph, and with the Chebyshev approximation,
+// and reports how far apart they are. It needs a full eigendecomposition, so it is meant for small graphs,
+// e.g. to choose the order before filtering a large graph.
+func (c *Chebyshev) CompareExact(graph *graphs.Graph, kernel Kernel, signal signals.Signal) (ApproximationError, error) {
+	basis, err := graph.Basis()
+	if err != nil {
+		return ApproximationError{}, err
+	}
+
+	response := make([]float64, basis.Size())
+	report := ApproximationError{}
+	for i, lambda := range basis.Eigenvalues {
+		response[i] = kernel(lambda)
+		report.Spectral = math.Max(report.Spectral, math.Abs(response[i]-c.Evaluate(lambda)))
+	}
+
+	exact, err := SpectralFilter(basis, response, signal)
+	if err != nil {
+		return ApproximationError{}, err
+	}
+	approx, err := c.Apply(graph, signal)
+	if err != nil {
+		return ApproximationError{}, err
+	}
+
+	diff, norm := 0.0, 0.0
+	for i := range exact {
+		diff += (exact[i] - approx[i]) * (exact[i] - approx[i])
+		norm += exact[i] * exact[i]
+	}
+	if norm > 0 {
+		report.Signal = math.Sqrt(diff / norm)
+	} else {
+		report.Signal = math.Sqrt(diff)
+	}
+	return report, nil
+}
+
+// LMaxBound returns an upper bound on the largest eigenvalue of the graph's Laplacian from Gershgorin's circle
+// theorem, in O(E). It is a cheap and safe choice of LMax for NewChebyshev.
+func LMaxBound(graph *graphs.Graph) float64 {
+	laplacian := graph.SparseLaplacian()
+	bound := 0.0
+	for i := 0; i < laplacian.Rows; i++ {
+		row := 0.0
+		for k := laplacian.RowPtr[i]; k < laplacian.RowPtr[i+1]; k++ {
+			row += math.Abs(laplacian.Values[k])
+		}
+		bound = math.Max(bound, row)
+	}
+	return bound
+}
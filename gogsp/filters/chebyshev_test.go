@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"example/gogsp/graphs"
+	"math"
+	"testing"
+)
+
+func TestChebyshevCompareExact(t *testing.T) {
+	g, err := graphs.Ring(30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lmax := LMaxBound(g)
+	if lmax < basis.LMax {
+		t.Fatalf("LMaxBound %g is below the largest eigenvalue %g", lmax, basis.LMax)
+	}
+	heat := func(lambda float64) float64 { return math.Exp(-2 * lambda) }
+	x := randomSignal(30, 1)
+
+	// The exact output, computed directly in the Fourier basis
+	spectrum, err := basis.GraphFourierTransform(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, lambda := range basis.Eigenvalues {
+		spectrum[i] *= heat(lambda)
+	}
+	exact, err := basis.InverseGraphFourierTransform(spectrum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := math.Inf(1)
+	for _, order := range []int{2, 4, 8, 16} {
+		c, err := NewChebyshev(heat, lmax, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := c.CompareExact(g, heat, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		approx, err := c.Apply(g, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := relativeError(exact, approx); math.Abs(report.Signal-want) > 1e-12*math.Max(1, want) {
+			t.Errorf("order %d: reported signal error %g, want %g", order, report.Signal, want)
+		}
+		spectral := 0.0
+		for _, lambda := range basis.Eigenvalues {
+			spectral = math.Max(spectral, math.Abs(heat(lambda)-c.Evaluate(lambda)))
+		}
+		if report.Spectral != spectral {
+			t.Errorf("order %d: reported spectral error %g, want %g", order, report.Spectral, spectral)
+		}
+		if report.Signal > (1+1e-9)*report.Spectral/relativeNorm(exact, x) {
+			t.Errorf("order %d: signal error %g exceeds what the spectral error %g allows", order, report.Signal, report.Spectral)
+		}
+		if report.Signal >= previous {
+			t.Errorf("order %d: error %g did not fall from %g", order, report.Signal, previous)
+		}
+		previous = report.Signal
+	}
+	if previous > 1e-8 {
+		t.Errorf("order 16 is still off by %g", previous)
+	}
+}
+
+// relativeNorm returns ||y|| / ||x||. Since ||g(L) x - p(L) x|| <= Spectral ||x||, the signal error of a report is
+// at most Spectral / relativeNorm(g(L) x, x).
+func relativeNorm(y, x []float64) float64 {
+	ny, nx := 0.0, 0.0
+	for i := range x {
+		ny += y[i] * y[i]
+		nx += x[i] * x[i]
+	}
+	return math.Sqrt(ny / nx)
+}
@@ -6,7 +6,7 @@
 package filters
 
 import (
-	"errors"
+	"example/gogsp/core"
 	"example/gogsp/graphs"
 	"example/gogsp/signals"
 )
@@ -14,12 +14,15 @@ import (
 type FilterFunc func(graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error)
 
 func ApplyFilter(filter FilterFunc, graph *graphs.Graph, signal signals.Signal) (signals.Signal, error) {
+	if err := signal.CheckSize(graph); err != nil {
+		return nil, err
+	}
 	graph.UpdateAdjacencyMatrix()
 	coefficients := make([]float64, len(signal))
 
-	for node := range signal {
+	for i, node := range graph.Nodes() {
 		// Use degree as coefficient - nodes with higher degree are considered more important
-		coefficients[node] = float64(len(graph.AdjacencyList[graphs.Node(node)]))
+		coefficients[i] = float64(len(graph.AdjacencyList[node]))
 	}
 
 	filteredSignal, err := filter(graph, coefficients, signal)
@@ -31,44 +34,104 @@ func ApplyFilter(filter FilterFunc, graph *graphs.Graph, signal signals.Signal)
 
 var LaplacianFilter FilterFunc = func(graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
 	if len(coefficients) != len(signal) {
-		return nil, errors.New("mismatch in size between coefficients and signal")
+		return nil, &core.SizeMismatchError{What: "coefficients", Got: len(coefficients), Of: "signal", Want: len(signal)}
 	}
 
-	output := make(signals.Signal, len(signal))
-	for node := range signal {
-		total := 0.0
-		edges := graph.AdjacencyList[graphs.Node(node)]
-		for _, edge := range edges {
-			total += float64(edge.Weight) * (signal[node] - signal[edge.Node])
-		}
-		// multiply with calculated coefficients
-		output[node] = coefficients[node] * total
+	// (L x)_i = sum over the edges of node i of weight * (x_i - x_j), computed on the sparse Laplacian
+	laplacian := graph.SparseLaplacian()
+	if laplacian.Rows != len(signal) {
+		return nil, &core.SizeMismatchError{What: "signal", Got: len(signal), Of: "graph", Want: laplacian.Rows}
+	}
+	output := signals.Signal(laplacian.MulVec(signal))
+
+	// multiply with calculated coefficients
+	for i := range output {
+		output[i] *= coefficients[i]
 	}
 
 	return output, nil
 }
 
-var HighPassFilter FilterFunc = func(graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
-	// same code as before, but coefficient calculation is removed 
-	if len(coefficients) != len(signal) {
-		return nil, errors.New("mismatch in size between coefficients and signal")
-	}
+// PolynomialFilter applies h(L) x = sum_k h[k] L^k x in the vertex domain, where L is the sparse Laplacian of the
+// graph. It costs len(h)-1 sparse matrix-vector products and never builds a dense matrix.
+func PolynomialFilter(graph *graphs.Graph, h []float64, signal signals.Signal) (signals.Signal, error) {
+	return polynomialFilter(graph.SparseLaplacian(), h, signal)
+}
 
+func polynomialFilter(laplacian *core.CSR, h []float64, signal signals.Signal) (signals.Signal, error) {
+	if laplacian.Rows != len(signal) {
+		return nil, &core.SizeMismatchError{What: "signal", Got: len(signal), Of: "graph", Want: laplacian.Rows}
+	}
 	output := make(signals.Signal, len(signal))
-	for node := range signal {
-		output[node] = coefficients[node] * signal[node]
+	if len(h) == 0 {
+		return output, nil
+	}
+
+	// Horner's scheme: y = h[K]; y = L y + h[k] x for k = K-1 down to 0
+	for i := range output {
+		output[i] = h[len(h)-1] * signal[i]
+	}
+	for k := len(h) - 2; k >= 0; k-- {
+		output = laplacian.MulVec(output)
+		for i := range output {
+			output[i] += h[k] * signal[i]
+		}
 	}
 	return output, nil
 }
 
+// LowPassFilter is a FilterFunc that keeps the lower half of the graph spectrum with a second order Butterworth
+// response. The per-node coefficients computed by ApplyFilter are not used, only their number is checked.
+//
+// Deprecated: LowPassFilter builds an exact filter on every call. Use NewLowPass, which takes the method, response
+// and cutoff, and apply the returned Filter to every signal.
+var LowPassFilter FilterFunc = func(graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
+	return passFilter(NewLowPass, graph, coefficients, signal)
+}
+
+// HighPassFilter is a FilterFunc that keeps the upper half of the graph spectrum with a second order Butterworth
+// response, the power complement of LowPassFilter. The per-node coefficients computed by ApplyFilter are not used,
+// only their number is checked.
+//
+// Deprecated: HighPassFilter builds an exact filter on every call. Use NewHighPass, which takes the method, response
+// and cutoff, and apply the returned Filter to every signal.
+var HighPassFilter FilterFunc = func(graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
+	return passFilter(NewHighPass, graph, coefficients, signal)
+}
+
+// passFilter applies the exact Butterworth(2) filter with a cutoff at lmax/2 built by NewLowPass or NewHighPass.
+func passFilter(build func(*graphs.Graph, Method, Response, Cutoff) (*Filter, error), graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
+	if len(coefficients) != len(signal) {
+		return nil, &core.SizeMismatchError{What: "coefficients", Got: len(coefficients), Of: "signal", Want: len(signal)}
+	}
+	filter, err := build(graph, MethodExact, Butterworth(2), FractionOfLMax(0.5))
+	if err != nil {
+		return nil, err
+	}
+	return filter.Apply(signal)
+}
 
 var FourierFilter FilterFunc = func(graph *graphs.Graph, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
+	// The basis is cached on the graph, so repeated calls on the same graph factorize the Laplacian only once
+	basis, err := graph.Basis()
+	if err != nil {
+		return nil, err
+	}
+	return SpectralFilter(basis, coefficients, signal)
+}
+
+// SpectralFilter scales each graph Fourier coefficient of the signal by the matching coefficient and transforms back.
+// Passing the same basis for many signals avoids recomputing the eigendecomposition of the Laplacian.
+func SpectralFilter(basis *core.Basis, coefficients []float64, signal signals.Signal) (signals.Signal, error) {
 	if len(coefficients) != len(signal) {
-		return nil, errors.New("mismatch in size between coefficients and signal")
+		return nil, &core.SizeMismatchError{What: "coefficients", Got: len(coefficients), Of: "signal", Want: len(signal)}
 	}
 
 	// Compute graph Fourier transform of the signal
-	ftSignal := graph.FourierTransform(signal)
+	ftSignal, err := basis.GraphFourierTransform(signal)
+	if err != nil {
+		return nil, err
+	}
 
 	output := make(signals.Signal, len(signal))
 
@@ -79,7 +142,5 @@ var FourierFilter FilterFunc = func(graph *graphs.Graph, coefficients []float64,
 	}
 
 	// Apply inverse Fourier transform to get back to the spatial domain
-	filteredSignal := graph.InverseFourierTransform(output)
-
-	return filteredSignal, nil
+	return basis.InverseGraphFourierTransform(output)
 }
@@ -0,0 +1,42 @@
+package filters
+
+import (
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"testing"
+)
+
+// TestReadmeExample runs the usage example of the README.
+func TestReadmeExample(t *testing.T) {
+	g := graphs.NewGraph()
+	g.AddNode(1)
+	g.AddNode(2)
+	g.AddEdge(1, 2, 3.5)
+
+	signal := signals.CreateSignal(2)
+	if err := signal.SetSignal(g, []float64{3, 7}); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := signal.At(g, 2); err != nil || value != 7 {
+		t.Errorf("node 2 has %v, %v, want 7", value, err)
+	}
+
+	filteredSignal, err := ApplyFilter(LaplacianFilter, g, signal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// L x = 3.5 (x_1 - x_2) on node 1 and its opposite on node 2, scaled by the degrees, both 1
+	checkSignal(t, filteredSignal, []float64{-14, 14})
+}
+
+func checkSignal(t *testing.T, got signals.Signal, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
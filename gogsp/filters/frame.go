@@ -0,0 +1,222 @@
+// frame.go contains the frame analysis of filter banks.
+// Analysing a signal with a bank of kernels g_k and synthesizing the result applies the frame operator
+// G(λ) = sum_k g_k(λ)² to it. The bank is a frame when A <= G(λ) <= B on the spectrum for some A > 0, a tight frame
+// when A = B, and a Parseval frame when A = B = 1, in which case synthesis exactly undoes analysis.
+// Any frame can be turned into a bank that reconstructs perfectly, either with its canonical dual or by
+// completing it into a Parseval frame.
+
+package filters
+
+import (
+	"errors"
+	"example/gogsp/core"
+	"example/gogsp/signals"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// FrameSamples is the number of points of [0, lmax] on which the frame operator is evaluated for the approximate
+// methods, which do not know the eigenvalues.
+const FrameSamples = 1000
+
+// FrameBounds holds the bounds A and B of the frame operator over the spectrum.
+type FrameBounds struct {
+	Lower, Upper float64
+}
+
+// IsFrame reports whether every frequency can be recovered from the analysis coefficients.
+func (b FrameBounds) IsFrame() bool {
+	return b.Lower > 0
+}
+
+// IsTight reports whether the bounds are equal up to the relative tolerance.
+func (b FrameBounds) IsTight(tolerance float64) bool {
+	return b.Upper-b.Lower <= tolerance*b.Upper
+}
+
+// ConditionNumber returns B / A, how much reconstruction can amplify errors in the coefficients.
+func (b FrameBounds) ConditionNumber() float64 {
+	return b.Upper / b.Lower
+}
+
+// FrameOperator returns G(λ) = sum_k g_k(λ)² at each of the given eigenvalues.
+func (f *Filter) FrameOperator(lambdas []float64) []float64 {
+	frame := make([]float64, len(lambdas))
+	for _, response := range f.Evaluate(lambdas) {
+		for i, g := range response {
+			frame[i] += g * g
+		}
+	}
+	return frame
+}
+
+// FrameBounds evaluates the frame operator on the graph spectrum and returns its extreme values. The spectrum is the
+// exact eigenvalues for MethodExact, and FrameSamples points of [0, lmax] otherwise.
+func (f *Filter) FrameBounds() (FrameBounds, error) {
+	lambdas, err := f.spectrum()
+	if err != nil {
+		return FrameBounds{}, err
+	}
+	bounds := FrameBounds{Lower: math.Inf(1), Upper: math.Inf(-1)}
+	for _, value := range f.FrameOperator(lambdas) {
+		bounds.Lower = math.Min(bounds.Lower, value)
+		bounds.Upper = math.Max(bounds.Upper, value)
+	}
+	return bounds, nil
+}
+
+// spectrum returns the frequencies on which the bank is checked, see FrameBounds.
+func (f *Filter) spectrum() ([]float64, error) {
+	if f.Method == MethodExact {
+		basis, err := f.Graph.Basis()
+		if err != nil {
+			return nil, err
+		}
+		return basis.Eigenvalues, nil
+	}
+	lmax := f.lmax()
+	lambdas := make([]float64, FrameSamples)
+	for i := range lambdas {
+		lambdas[i] = lmax * float64(i) / float64(len(lambdas)-1)
+	}
+	return lambdas, nil
+}
+
+// frameOperatorAt returns G(λ) at a single frequency.
+func (f *Filter) frameOperatorAt(lambda float64) float64 {
+	sum := 0.0
+	for _, kernel := range f.Kernels {
+		g := kernel(lambda)
+		sum += g * g
+	}
+	return sum
+}
+
+// CanonicalDual returns the bank of kernels g_k(λ) / G(λ). Synthesizing the coefficients of f with the dual gives
+// back the analysed signal, on every frequency where G is not zero.
+func (f *Filter) CanonicalDual() (*Filter, error) {
+	bounds, err := f.FrameBounds()
+	if err != nil {
+		return nil, err
+	}
+	if !bounds.IsFrame() {
+		return nil, errors.New("the filter bank is not a frame, its frame operator vanishes on the spectrum")
+	}
+	kernels := make([]Kernel, len(f.Kernels))
+	for k, kernel := range f.Kernels {
+		kernel := kernel
+		kernels[k] = func(lambda float64) float64 {
+			frame := f.frameOperatorAt(lambda)
+			if frame == 0 {
+				return 0
+			}
+			return kernel(lambda) / frame
+		}
+	}
+	return f.withKernels(kernels), nil
+}
+
+// ParsevalCompletion returns a Parseval frame made of the kernels g_k(λ) / sqrt(B) and one extra kernel
+// sqrt(1 - G(λ) / B), where B is the upper frame bound. The original kernels are kept up to a constant, so the
+// coefficients keep their meaning, and analysis followed by synthesis with the completed bank is the identity.
+func (f *Filter) ParsevalCompletion() (*Filter, error) {
+	bounds, err := f.FrameBounds()
+	if err != nil {
+		return nil, err
+	}
+	if bounds.Upper <= 0 {
+		return nil, errors.New("the filter bank is zero on the whole spectrum")
+	}
+	scale := math.Sqrt(bounds.Upper)
+	kernels := make([]Kernel, 0, len(f.Kernels)+1)
+	for _, kernel := range f.Kernels {
+		kernel := kernel
+		kernels = append(kernels, func(lambda float64) float64 {
+			return kernel(lambda) / scale
+		})
+	}
+	kernels = append(kernels, func(lambda float64) float64 {
+		return math.Sqrt(math.Max(0, 1-f.frameOperatorAt(lambda)/bounds.Upper))
+	})
+	return f.withKernels(kernels), nil
+}
+
+// withKernels returns a filter with the same graph and settings as f but other kernels. The interval of f is fixed
+// on the copy, so that both banks are approximated on the same spectrum.
+func (f *Filter) withKernels(kernels []Kernel) *Filter {
+	filter := *f
+	filter.Kernels = kernels
+	filter.LMax = f.lmax()
+	return &filter
+}
+
+// Reconstruct returns the signal whose analysis is closest to the coefficients, i.e. it applies the pseudo-inverse
+// of the frame. With MethodExact this is done in the Fourier basis; with MethodChebyshev the normal equations
+// (sum_k g_k(L)²) x = sum_k g_k(L) c_k are solved by conjugate gradients. MethodLanczos is not supported: its
+// approximation depends on the vector it is applied to, so the operator of the normal equations is not linear and
+// conjugate gradients would have no reason to converge.
+func (f *Filter) Reconstruct(coefficients *mat.Dense) (signals.Signal, error) {
+	if f.Method == MethodLanczos {
+		return nil, errors.New("reconstruction needs a linear frame operator, use MethodExact or MethodChebyshev instead of MethodLanczos")
+	}
+
+	if f.Method == MethodExact {
+		adjoint, err := f.Synthesize(coefficients)
+		if err != nil {
+			return nil, err
+		}
+		basis, err := f.Graph.Basis()
+		if err != nil {
+			return nil, err
+		}
+		spectrum, err := basis.GraphFourierTransform(adjoint)
+		if err != nil {
+			return nil, err
+		}
+		frame := f.FrameOperator(basis.Eigenvalues)
+		for i := range spectrum {
+			if frame[i] > 0 {
+				spectrum[i] /= frame[i]
+			} else {
+				spectrum[i] = 0
+			}
+		}
+		return basis.InverseGraphFourierTransform(spectrum)
+	}
+
+	// Fix lmax and the expansions once, rather than on each of the many products of the conjugate gradients
+	filter := *f
+	filter.LMax = f.lmax()
+	adjoint, err := filter.Synthesize(coefficients)
+	if err != nil {
+		return nil, err
+	}
+	approximations, err := filter.chebyshev()
+	if err != nil {
+		return nil, err
+	}
+	laplacian := f.Graph.SparseLaplacian()
+	operator := func(v []float64) ([]float64, error) {
+		output := make([]float64, len(v))
+		for _, approximation := range approximations {
+			analyzed, err := approximation.apply(laplacian, v)
+			if err != nil {
+				return nil, err
+			}
+			synthesized, err := approximation.apply(laplacian, analyzed)
+			if err != nil {
+				return nil, err
+			}
+			for i := range output {
+				output[i] += synthesized[i]
+			}
+		}
+		return output, nil
+	}
+	x, _, err := core.ConjugateGradient(operator, adjoint, 1e-10, 10*len(adjoint)+100)
+	if err != nil {
+		return nil, err
+	}
+	return x, nil
+}
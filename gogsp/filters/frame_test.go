@@ -0,0 +1,172 @@
+package filters
+
+import (
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// randomSignal returns a signal with standard normal values.
+func randomSignal(n int, seed int64) signals.Signal {
+	rng := rand.New(rand.NewSource(seed))
+	s := make(signals.Signal, n)
+	for i := range s {
+		s[i] = rng.NormFloat64()
+	}
+	return s
+}
+
+// relativeError returns ||x - y|| / ||x||.
+func relativeError(x, y []float64) float64 {
+	diff, norm := 0.0, 0.0
+	for i := range x {
+		diff += (x[i] - y[i]) * (x[i] - y[i])
+		norm += x[i] * x[i]
+	}
+	return math.Sqrt(diff / norm)
+}
+
+// mexicanHat returns a Mexican hat bank on a 40-node path, a frame that is far from tight and whose kernels are
+// smooth enough for an accurate Chebyshev approximation.
+func mexicanHat(t *testing.T, method Method) *Filter {
+	t.Helper()
+	g, err := graphs.Path(40, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank, err := NewMexicanHat(g, method, 5, 4, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method == MethodChebyshev {
+		bank.Order = 200
+	}
+	return bank.Filter
+}
+
+func TestFrameBounds(t *testing.T) {
+	filter := mexicanHat(t, MethodExact)
+	bounds, err := filter.FrameBounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	basis, err := filter.Graph.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lower, upper := math.Inf(1), math.Inf(-1)
+	for _, lambda := range basis.Eigenvalues {
+		sum := 0.0
+		for _, kernel := range filter.Kernels {
+			sum += kernel(lambda) * kernel(lambda)
+		}
+		lower, upper = math.Min(lower, sum), math.Max(upper, sum)
+	}
+	if bounds.Lower != lower || bounds.Upper != upper {
+		t.Errorf("got bounds [%g, %g], want [%g, %g]", bounds.Lower, bounds.Upper, lower, upper)
+	}
+	if !bounds.IsFrame() || bounds.IsTight(0.1) {
+		t.Errorf("bounds [%g, %g] should be those of a frame that is not tight", bounds.Lower, bounds.Upper)
+	}
+
+	parseval, err := filter.ParsevalCompletion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds, err = parseval.FrameBounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(bounds.Lower-1) > 1e-12 || math.Abs(bounds.Upper-1) > 1e-12 {
+		t.Errorf("the Parseval completion has bounds [%g, %g], want [1, 1]", bounds.Lower, bounds.Upper)
+	}
+}
+
+func TestFrameReconstruction(t *testing.T) {
+	for _, method := range []Method{MethodExact, MethodChebyshev} {
+		filter := mexicanHat(t, method)
+		x := randomSignal(40, 1)
+		coefficients, err := filter.Analyze(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reconstructed, err := filter.Reconstruct(coefficients)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := relativeError(x, reconstructed); e > 1e-8 {
+			t.Errorf("method %d: Reconstruct is off by %g", method, e)
+		}
+
+		dual, err := filter.CanonicalDual()
+		if err != nil {
+			t.Fatal(err)
+		}
+		synthesized, err := dual.Synthesize(coefficients)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := relativeError(x, synthesized); e > 1e-8 {
+			t.Errorf("method %d: synthesis with the canonical dual is off by %g", method, e)
+		}
+	}
+
+	// Without an lmax, Reconstruct estimates one for all of its iterations and leaves the filter as it was
+	filter := mexicanHat(t, MethodChebyshev)
+	filter.LMax = 0
+	x := randomSignal(40, 3)
+	coefficients, err := filter.Analyze(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconstructed, err := filter.Reconstruct(coefficients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := relativeError(x, reconstructed); e > 1e-8 || filter.LMax != 0 {
+		t.Errorf("Reconstruct without lmax is off by %g and sets lmax %g", e, filter.LMax)
+	}
+
+	// Parseval frames are their own dual
+	parseval, err := mexicanHat(t, MethodExact).ParsevalCompletion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x = randomSignal(40, 2)
+	coefficients, err = parseval.Analyze(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	synthesized, err := parseval.Synthesize(coefficients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := relativeError(x, synthesized); e > 1e-8 {
+		t.Errorf("synthesis with the Parseval completion is off by %g", e)
+	}
+}
+
+func TestFrameLanczos(t *testing.T) {
+	filter := mexicanHat(t, MethodExact)
+	filter.Method = MethodLanczos
+	filter.Lanczos = core.LanczosOptions{MaxIterations: 40, Tolerance: 1e-9, Seed: 3}
+	coefficients := mat.NewDense(40, len(filter.Kernels), nil)
+	if _, err := filter.Reconstruct(coefficients); err == nil {
+		t.Error("Reconstruct accepts MethodLanczos")
+	}
+
+	dual, err := filter.CanonicalDual()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dual.Method != filter.Method || dual.Lanczos != filter.Lanczos || dual.LMax != filter.LMax {
+		t.Errorf("the dual has method %d, lmax %g and options %+v, want %d, %g and %+v",
+			dual.Method, dual.LMax, dual.Lanczos, filter.Method, filter.LMax, filter.Lanczos)
+	}
+}
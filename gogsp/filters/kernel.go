@@ -0,0 +1,239 @@
+// kernel.go contains the Filter type, a spectral filter (or filter bank) defined by its kernels g(λ).
+// Unlike a FilterFunc, which takes arbitrary per-node coefficients, a Filter is described in the graph frequency
+// domain: g(λ) is the gain applied to the Fourier mode of eigenvalue λ.
+// A Filter can be applied exactly, in the eigenbasis of the Laplacian, or approximately with Chebyshev polynomials
+// or the Lanczos iteration on the sparse Laplacian, which need no eigendecomposition.
+
+package filters
+
+import (
+	"errors"
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Method selects how a Filter is applied to a signal.
+type Method int
+
+const (
+	// MethodExact filters in the Fourier basis of the graph, which costs a full eigendecomposition once per graph
+	MethodExact Method = iota
+	// MethodChebyshev filters with a Chebyshev approximation of every kernel, see Chebyshev
+	MethodChebyshev
+	// MethodLanczos filters with a Lanczos approximation of every kernel for each signal, see core.CSR.Function
+	MethodLanczos
+)
+
+// DefaultChebyshevOrder is the order used by MethodChebyshev when Filter.Order is zero.
+const DefaultChebyshevOrder = 30
+
+// Filter is a bank of one or more spectral kernels on a graph.
+type Filter struct {
+	Graph   *graphs.Graph
+	Kernels []Kernel
+
+	// Method is the way Apply, Analyze and Synthesize filter signals
+	Method Method
+	// Order is the order of the Chebyshev approximation, DefaultChebyshevOrder if zero
+	Order int
+	// LMax bounds the spectrum for MethodChebyshev. If zero, EstimateLMax is used
+	LMax float64
+	// Lanczos controls the iteration of MethodLanczos and of the lmax estimate
+	Lanczos core.LanczosOptions
+}
+
+// NewFilter returns an exact filter on the graph with the given kernels.
+func NewFilter(graph *graphs.Graph, kernels ...Kernel) *Filter {
+	return &Filter{Graph: graph, Kernels: kernels, Method: MethodExact}
+}
+
+// Evaluate returns the frequency response of every kernel at the given eigenvalues, indexed [kernel][λ].
+func (f *Filter) Evaluate(lambdas []float64) [][]float64 {
+	response := make([][]float64, len(f.Kernels))
+	for k, kernel := range f.Kernels {
+		response[k] = make([]float64, len(lambdas))
+		for i, lambda := range lambdas {
+			response[k][i] = kernel(lambda)
+		}
+	}
+	return response
+}
+
+// Apply filters the signal with a single-kernel filter.
+func (f *Filter) Apply(signal signals.Signal) (signals.Signal, error) {
+	if len(f.Kernels) != 1 {
+		return nil, fmt.Errorf("apply needs a filter with one kernel, this one has %d, use Analyze instead", len(f.Kernels))
+	}
+	coefficients, err := f.Analyze(signal)
+	if err != nil {
+		return nil, err
+	}
+	return signals.Signal(mat.Col(nil, 0, coefficients)), nil
+}
+
+// Analyze filters the signal with every kernel. It returns an N x Nf matrix whose column k is g_k(L) x.
+func (f *Filter) Analyze(signal signals.Signal) (*mat.Dense, error) {
+	if len(f.Kernels) == 0 {
+		return nil, errors.New("filter has no kernels")
+	}
+	if err := signal.CheckSize(f.Graph); err != nil {
+		return nil, err
+	}
+	coefficients := mat.NewDense(len(signal), len(f.Kernels), nil)
+
+	switch f.Method {
+	case MethodExact:
+		basis, err := f.Graph.Basis()
+		if err != nil {
+			return nil, err
+		}
+		spectrum, err := basis.GraphFourierTransform(signal)
+		if err != nil {
+			return nil, err
+		}
+		response := f.Evaluate(basis.Eigenvalues)
+		for k := range f.Kernels {
+			filtered, err := basis.InverseGraphFourierTransform(scaled(spectrum, response[k]))
+			if err != nil {
+				return nil, err
+			}
+			coefficients.SetCol(k, filtered)
+		}
+	case MethodChebyshev:
+		approximations, err := f.chebyshev()
+		if err != nil {
+			return nil, err
+		}
+		laplacian := f.Graph.SparseLaplacian()
+		for k, approximation := range approximations {
+			filtered, err := approximation.apply(laplacian, signal)
+			if err != nil {
+				return nil, err
+			}
+			coefficients.SetCol(k, filtered)
+		}
+	case MethodLanczos:
+		if err := f.Graph.CheckSymmetric(); err != nil {
+			return nil, err
+		}
+		laplacian := f.Graph.SparseLaplacian()
+		for k, kernel := range f.Kernels {
+			filtered, err := f.lanczos(laplacian, kernel, signal)
+			if err != nil {
+				return nil, err
+			}
+			coefficients.SetCol(k, filtered)
+		}
+	default:
+		return nil, fmt.Errorf("unknown filter method %d", f.Method)
+	}
+	return coefficients, nil
+}
+
+// Synthesize is the adjoint of Analyze: it returns sum_k g_k(L) c_k, where c_k is column k of the N x Nf
+// coefficient matrix. Analysis followed by synthesis applies sum_k g_k(L)^2 to the signal.
+func (f *Filter) Synthesize(coefficients *mat.Dense) (signals.Signal, error) {
+	r, c := coefficients.Dims()
+	if c != len(f.Kernels) {
+		return nil, fmt.Errorf("coefficients have %d columns but the filter has %d kernels", c, len(f.Kernels))
+	}
+	if r != len(f.Graph.AdjacencyList) {
+		return nil, &core.SizeMismatchError{What: "coefficients", Got: r, Of: "graph", Want: len(f.Graph.AdjacencyList)}
+	}
+	output := make(signals.Signal, r)
+
+	switch f.Method {
+	case MethodExact:
+		basis, err := f.Graph.Basis()
+		if err != nil {
+			return nil, err
+		}
+		response := f.Evaluate(basis.Eigenvalues)
+		spectrum := make(signals.Signal, r)
+		for k := range f.Kernels {
+			ck, err := basis.GraphFourierTransform(mat.Col(nil, k, coefficients))
+			if err != nil {
+				return nil, err
+			}
+			for i := range spectrum {
+				spectrum[i] += response[k][i] * ck[i]
+			}
+		}
+		return basis.InverseGraphFourierTransform(spectrum)
+	case MethodChebyshev:
+		approximations, err := f.chebyshev()
+		if err != nil {
+			return nil, err
+		}
+		laplacian := f.Graph.SparseLaplacian()
+		for k, approximation := range approximations {
+			filtered, err := approximation.apply(laplacian, mat.Col(nil, k, coefficients))
+			if err != nil {
+				return nil, err
+			}
+			for i := range output {
+				output[i] += filtered[i]
+			}
+		}
+	case MethodLanczos:
+		if err := f.Graph.CheckSymmetric(); err != nil {
+			return nil, err
+		}
+		laplacian := f.Graph.SparseLaplacian()
+		for k, kernel := range f.Kernels {
+			filtered, err := f.lanczos(laplacian, kernel, mat.Col(nil, k, coefficients))
+			if err != nil {
+				return nil, err
+			}
+			for i := range output {
+				output[i] += filtered[i]
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown filter method %d", f.Method)
+	}
+	return output, nil
+}
+
+// chebyshev returns the Chebyshev approximation of every kernel of the filter.
+func (f *Filter) chebyshev() ([]*Chebyshev, error) {
+	order := f.Order
+	if order == 0 {
+		order = DefaultChebyshevOrder
+	}
+	lmax := f.lmax()
+	approximations := make([]*Chebyshev, len(f.Kernels))
+	for k, kernel := range f.Kernels {
+		var err error
+		approximations[k], err = NewChebyshev(kernel, lmax, order)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return approximations, nil
+}
+
+// lmax returns the upper end of the spectrum used by the approximate methods.
+func (f *Filter) lmax() float64 {
+	if f.LMax > 0 {
+		return f.LMax
+	}
+	if bound := EstimateLMax(f.Graph, f.Lanczos); bound > 0 {
+		return bound
+	}
+	// A graph without edges has L = 0, any interval containing 0 will do
+	return 1
+}
+
+// scaled returns the element-wise product of the spectrum and the response.
+func scaled(spectrum signals.Signal, response []float64) signals.Signal {
+	output := make(signals.Signal, len(spectrum))
+	for i := range spectrum {
+		output[i] = response[i] * spectrum[i]
+	}
+	return output
+}
@@ -0,0 +1,115 @@
+package filters
+
+import (
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// smoothBank returns a filter on a 60-node ring with a low-pass and a band-pass kernel, both smooth enough for the
+// approximate methods to agree with the exact one.
+func smoothBank(t *testing.T, method Method) *Filter {
+	t.Helper()
+	g, err := graphs.Ring(60, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := NewFilter(g,
+		func(lambda float64) float64 { return math.Exp(-lambda) },
+		func(lambda float64) float64 { return lambda * math.Exp(-lambda) },
+	)
+	filter.Method = method
+	filter.Order = 50
+	filter.Lanczos = core.LanczosOptions{Seed: 1}
+	return filter
+}
+
+func TestFilterMethodsAgree(t *testing.T) {
+	x := randomSignal(60, 1)
+	exact, err := smoothBank(t, MethodExact).Analyze(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []Method{MethodChebyshev, MethodLanczos} {
+		coefficients, err := smoothBank(t, method).Analyze(x)
+		if err != nil {
+			t.Fatalf("method %d: %v", method, err)
+		}
+		for k := 0; k < 2; k++ {
+			if e := relativeError(mat.Col(nil, k, exact), mat.Col(nil, k, coefficients)); e > 1e-8 {
+				t.Errorf("method %d: kernel %d is %g away from the exact filter", method, k, e)
+			}
+		}
+	}
+}
+
+// TestFilterAdjoint checks <Analyze x, c> = <x, Synthesize c> for every method.
+func TestFilterAdjoint(t *testing.T) {
+	x := randomSignal(60, 2)
+	c := mat.NewDense(60, 2, append(randomSignal(60, 3), randomSignal(60, 4)...))
+	for _, method := range []Method{MethodExact, MethodChebyshev, MethodLanczos} {
+		filter := smoothBank(t, method)
+		analyzed, err := filter.Analyze(x)
+		if err != nil {
+			t.Fatalf("method %d: %v", method, err)
+		}
+		synthesized, err := filter.Synthesize(c)
+		if err != nil {
+			t.Fatalf("method %d: %v", method, err)
+		}
+		left := mat.Sum(mulElem(analyzed, c))
+		right := mat.Dot(mat.NewVecDense(60, x), mat.NewVecDense(60, synthesized))
+		if math.Abs(left-right) > 1e-8*math.Abs(left) {
+			t.Errorf("method %d: <Analyze x, c> = %g but <x, Synthesize c> = %g", method, left, right)
+		}
+	}
+}
+
+func mulElem(a, b *mat.Dense) *mat.Dense {
+	var product mat.Dense
+	product.MulElem(a, b)
+	return &product
+}
+
+func TestFilterApply(t *testing.T) {
+	filter := smoothBank(t, MethodExact)
+	x := randomSignal(60, 5)
+	if _, err := filter.Apply(x); err == nil {
+		t.Error("a bank of 2 kernels is applied")
+	}
+	response := filter.Evaluate([]float64{0, 1})
+	if len(response) != 2 || response[0][0] != 1 || response[1][0] != 0 || response[1][1] != math.Exp(-1) {
+		t.Errorf("got the response %v", response)
+	}
+
+	// Apply is column 0 of Analyze on a single kernel
+	filter.Kernels = filter.Kernels[:1]
+	applied, err := filter.Apply(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzed, err := filter.Analyze(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := relativeError(mat.Col(nil, 0, analyzed), applied); e != 0 {
+		t.Errorf("Apply is %g away from Analyze", e)
+	}
+
+	if _, err := filter.Apply(x[:10]); err == nil {
+		t.Error("a signal of 10 values is filtered on 60 nodes")
+	}
+	if _, err := filter.Synthesize(mat.NewDense(60, 2, nil)); err == nil {
+		t.Error("2 columns of coefficients are synthesized with 1 kernel")
+	}
+	if _, err := NewFilter(filter.Graph).Analyze(x); err == nil {
+		t.Error("a filter without kernels is applied")
+	}
+	filter.Method = Method(7)
+	if _, err := filter.Apply(x); err == nil {
+		t.Error("an unknown method is applied")
+	}
+}
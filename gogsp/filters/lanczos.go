@@ -0,0 +1,43 @@
+// lanczos.go contains the Lanczos-based parts of the filters: MethodLanczos, which applies each kernel as
+// f(L) x ≈ ||x|| V f(T) e_1 on a Krylov basis of the sparse Laplacian, and EstimateLMax, which bounds the spectrum
+// for the Chebyshev approximation much more tightly than Gershgorin's theorem.
+// Unlike a Chebyshev polynomial, the Lanczos approximation adapts to the signal and stops by itself once it has
+// converged, so it handles kernels that are hard to approximate by a polynomial of fixed order, such as ideal cutoffs.
+
+package filters
+
+import (
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"fmt"
+	"math"
+)
+
+// EstimateLMax returns an upper bound on the largest eigenvalue of the graph's Laplacian, the Lanczos estimate
+// plus its error bound, or LMaxBound when it is tighter or the Lanczos iteration fails or does not converge.
+// Before convergence the error bound only tells how close the estimate is to some eigenvalue, which need not be
+// the largest, so it cannot be trusted to cover the spectrum.
+func EstimateLMax(graph *graphs.Graph, opts core.LanczosOptions) float64 {
+	bound := LMaxBound(graph)
+	if len(graph.AdjacencyList) == 0 {
+		return bound
+	}
+	estimate, err := graph.EstimateSpectrum(opts)
+	if err != nil || !estimate.Converged {
+		return bound
+	}
+	return math.Min(bound, estimate.UpperBound())
+}
+
+// lanczos filters the signal with one kernel, see MethodLanczos.
+func (f *Filter) lanczos(laplacian *core.CSR, kernel Kernel, signal signals.Signal) (signals.Signal, error) {
+	filtered, diagnostics, err := laplacian.Function(kernel, signal, f.Lanczos)
+	if err != nil {
+		return nil, err
+	}
+	if !diagnostics.Converged {
+		return nil, fmt.Errorf("lanczos did not converge in %d iterations, relative change %g", diagnostics.Iterations, diagnostics.Residual)
+	}
+	return filtered, nil
+}
@@ -0,0 +1,37 @@
+package filters
+
+import (
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"testing"
+)
+
+func TestEstimateLMax(t *testing.T) {
+	g, err := graphs.Path(200, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound := LMaxBound(g)
+
+	lmax := EstimateLMax(g, core.LanczosOptions{Seed: 1})
+	if lmax < basis.LMax || lmax > bound {
+		t.Errorf("got %g, want a bound in [%g, %g]", lmax, basis.LMax, bound)
+	}
+
+	// An unconverged estimate cannot be trusted and falls back to Gershgorin
+	opts := core.LanczosOptions{MaxIterations: 5, Seed: 1}
+	estimate, err := g.EstimateSpectrum(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Converged {
+		t.Fatal("5 iterations converged")
+	}
+	if lmax := EstimateLMax(g, opts); lmax != bound {
+		t.Errorf("got %g without convergence, want the Gershgorin bound %g", lmax, bound)
+	}
+}
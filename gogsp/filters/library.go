@@ -0,0 +1,385 @@
+// library.go contains the predefined filter banks of PyGSP: Heat, MexicanHat, Abspline, Meyer, Itersine, HalfCosine,
+// Simoncelli, Held and Papadimitriou. Each one is a Filter, so it can be applied in the eigenbasis or with
+// Chebyshev polynomials, and it keeps the parameters it was built with.
+// Meyer, Itersine, Simoncelli, Held and Papadimitriou are tight frames: sum_k g_k(λ)² is constant over [0, lmax].
+// HalfCosine is a uniform filter bank: sum_k g_k(λ) is constant instead.
+
+package filters
+
+import (
+	"errors"
+	"example/gogsp/graphs"
+	"fmt"
+	"math"
+)
+
+// bankLMax returns the lmax the bank is designed for, failing on graphs without edges whose spectrum is {0}.
+func bankLMax(graph *graphs.Graph, method Method) (float64, error) {
+	lmax, err := spectrumLMax(graph, method)
+	if err != nil {
+		return 0, err
+	}
+	if lmax <= 0 {
+		return 0, errors.New("the filter bank needs a graph with at least one edge")
+	}
+	return lmax, nil
+}
+
+// newBank wraps the kernels in a Filter applied with the given method on [0, lmax].
+func newBank(graph *graphs.Graph, method Method, lmax float64, kernels []Kernel) *Filter {
+	filter := NewFilter(graph, kernels...)
+	filter.Method = method
+	filter.LMax = lmax
+	return filter
+}
+
+// Heat is a bank of heat kernels g(λ) = exp(-τ λ / lmax), one per scale τ.
+type Heat struct {
+	*Filter
+	Scales []float64
+	// Normalize scales every kernel to unit norm over the eigenvalues of the graph
+	Normalize bool
+}
+
+// NewHeat returns a heat kernel for every scale. Normalizing needs the eigenvalues, so it computes the graph's basis
+// whatever the method.
+func NewHeat(graph *graphs.Graph, method Method, scales []float64, normalize bool) (*Heat, error) {
+	if len(scales) == 0 {
+		return nil, errors.New("heat filter needs at least one scale")
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	var eigenvalues []float64
+	if normalize {
+		basis, err := graph.Basis()
+		if err != nil {
+			return nil, err
+		}
+		eigenvalues = basis.Eigenvalues
+	}
+
+	kernels := make([]Kernel, len(scales))
+	for i, tau := range scales {
+		tau := tau
+		kernel := func(lambda float64) float64 {
+			return math.Min(math.Exp(-tau*lambda/lmax), 1)
+		}
+		norm := 1.0
+		if normalize {
+			norm = kernelNorm(kernel, eigenvalues)
+		}
+		kernels[i] = func(lambda float64) float64 {
+			return kernel(lambda) / norm
+		}
+	}
+	return &Heat{Filter: newBank(graph, method, lmax, kernels), Scales: scales, Normalize: normalize}, nil
+}
+
+// MexicanHat is a bank of band-pass kernels g(t λ) = t λ exp(-t λ) at log-spaced scales, plus a low-pass
+// kernel exp(-(λ / 0.4 lmin)^4) for the frequencies below lmin = lmax / LPFactor.
+type MexicanHat struct {
+	*Filter
+	Nf       int
+	LPFactor float64
+	Scales   []float64
+	// Normalize multiplies every band-pass kernel by the square root of its scale
+	Normalize bool
+}
+
+// NewMexicanHat returns a Mexican hat bank of nf filters, i.e. nf-1 band-pass kernels and one low-pass kernel.
+func NewMexicanHat(graph *graphs.Graph, method Method, nf int, lpfactor float64, normalize bool) (*MexicanHat, error) {
+	if nf < 2 {
+		return nil, fmt.Errorf("mexican hat needs at least 2 filters, got %d", nf)
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	lmin := lmax / lpfactor
+	scales := logScales(lmin, lmax, nf-1)
+
+	kernels := make([]Kernel, 0, nf)
+	kernels = append(kernels, func(lambda float64) float64 {
+		return 1.2 * math.Exp(-1) * math.Exp(-math.Pow(lambda/0.4/lmin, 4))
+	})
+	for _, t := range scales {
+		t := t
+		norm := 1.0
+		if normalize {
+			norm = math.Sqrt(t)
+		}
+		kernels = append(kernels, func(lambda float64) float64 {
+			return norm * t * lambda * math.Exp(-t*lambda)
+		})
+	}
+	return &MexicanHat{
+		Filter:    newBank(graph, method, lmax, kernels),
+		Nf:        nf,
+		LPFactor:  lpfactor,
+		Scales:    scales,
+		Normalize: normalize,
+	}, nil
+}
+
+// Abspline is the filter bank of the spectral graph wavelet transform, with the cubic spline kernel SGWTKernel at
+// log-spaced scales and a low-pass kernel for the frequencies below lmin = lmax / LPFactor. See SGWT.
+type Abspline struct {
+	*Filter
+	Nf       int
+	LPFactor float64
+	Scales   []float64
+}
+
+// NewAbspline returns an Abspline bank of nf filters, i.e. nf-1 wavelet kernels and one scaling function.
+func NewAbspline(graph *graphs.Graph, method Method, nf int, lpfactor float64) (*Abspline, error) {
+	if nf < 2 {
+		return nil, fmt.Errorf("abspline needs at least 2 filters, got %d", nf)
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	lmin := lmax / lpfactor
+	scales := logScales(lmin, lmax, nf-1)
+
+	kernels := make([]Kernel, 0, nf)
+	kernels = append(kernels, sgwtScaling(lmin))
+	for _, t := range scales {
+		t := t
+		kernels = append(kernels, func(lambda float64) float64 {
+			return SGWTKernel(t * lambda)
+		})
+	}
+	return &Abspline{Filter: newBank(graph, method, lmax, kernels), Nf: nf, LPFactor: lpfactor, Scales: scales}, nil
+}
+
+// Meyer is the tight frame of Meyer wavelets: a scaling function and nf-1 wavelets at dyadic scales.
+type Meyer struct {
+	*Filter
+	Nf     int
+	Scales []float64
+}
+
+// NewMeyer returns a Meyer bank of nf filters. The scales are 4/(3 lmax) 2^k for k = nf-2 down to 0.
+func NewMeyer(graph *graphs.Graph, method Method, nf int) (*Meyer, error) {
+	if nf < 2 {
+		return nil, fmt.Errorf("meyer needs at least 2 filters, got %d", nf)
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	scales := make([]float64, nf-1)
+	for i := range scales {
+		scales[i] = 4 / (3 * lmax) * math.Pow(2, float64(nf-2-i))
+	}
+
+	kernels := make([]Kernel, 0, nf)
+	kernels = append(kernels, func(lambda float64) float64 {
+		return meyerScaling(scales[0] * lambda)
+	})
+	for _, t := range scales {
+		t := t
+		kernels = append(kernels, func(lambda float64) float64 {
+			return meyerWavelet(t * lambda)
+		})
+	}
+	return &Meyer{Filter: newBank(graph, method, lmax, kernels), Nf: nf, Scales: scales}, nil
+}
+
+// meyerTransition is the polynomial v(x) = x^4 (35 - 84x + 70x² - 20x³) that joins 0 and 1 smoothly.
+func meyerTransition(x float64) float64 {
+	return x * x * x * x * (35 - 84*x + 70*x*x - 20*x*x*x)
+}
+
+func meyerScaling(x float64) float64 {
+	const l1, l2 = 2. / 3, 4. / 3
+	switch {
+	case x < l1:
+		return 1
+	case x < l2:
+		return math.Cos(math.Pi / 2 * meyerTransition(math.Abs(x)/l1-1))
+	}
+	return 0
+}
+
+func meyerWavelet(x float64) float64 {
+	const l1, l2, l3 = 2. / 3, 4. / 3, 8. / 3
+	switch {
+	case x < l1:
+		return 0
+	case x < l2:
+		return math.Sin(math.Pi / 2 * meyerTransition(math.Abs(x)/l1-1))
+	case x < l3:
+		return math.Cos(math.Pi / 2 * meyerTransition(math.Abs(x)/l2-1))
+	}
+	return 0
+}
+
+// Itersine is a tight frame of nf overlapping iterated sine windows spread uniformly over [0, lmax].
+type Itersine struct {
+	*Filter
+	Nf int
+	// Overlap is the number of windows covering each frequency
+	Overlap float64
+}
+
+// NewItersine returns an Itersine bank of nf filters.
+func NewItersine(graph *graphs.Graph, method Method, nf int, overlap float64) (*Itersine, error) {
+	if nf < 1 || overlap <= 0 || float64(nf)-overlap+1 <= 0 {
+		return nil, fmt.Errorf("itersine needs nf >= 1 and 0 < overlap <= nf+1, got nf = %d and overlap = %g", nf, overlap)
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	scale := lmax / (float64(nf) - overlap + 1) * overlap
+
+	window := func(x float64) float64 {
+		if x < -0.5 || x > 0.5 {
+			return 0
+		}
+		c := math.Cos(x * math.Pi)
+		return math.Sin(0.5 * math.Pi * c * c)
+	}
+	kernels := make([]Kernel, nf)
+	for i := range kernels {
+		center := (float64(i+1) - overlap/2) / overlap
+		kernels[i] = func(lambda float64) float64 {
+			return window(lambda/scale-center) * math.Sqrt(2/overlap)
+		}
+	}
+	return &Itersine{Filter: newBank(graph, method, lmax, kernels), Nf: nf, Overlap: overlap}, nil
+}
+
+// HalfCosine is a uniform filter bank of nf raised cosine windows spread over [0, lmax].
+type HalfCosine struct {
+	*Filter
+	Nf int
+}
+
+// NewHalfCosine returns a HalfCosine bank of nf filters, nf must be at least 3.
+func NewHalfCosine(graph *graphs.Graph, method Method, nf int) (*HalfCosine, error) {
+	if nf <= 2 {
+		return nil, fmt.Errorf("half cosine needs at least 3 filters, got %d", nf)
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	dilation := lmax * 3 / float64(nf-2)
+
+	window := func(x float64) float64 {
+		if x < 0 || x > dilation {
+			return 0
+		}
+		return 0.5 + 0.5*math.Cos(2*math.Pi*(x/dilation-0.5))
+	}
+	kernels := make([]Kernel, nf)
+	for i := range kernels {
+		shift := dilation / 3 * float64(i-2)
+		kernels[i] = func(lambda float64) float64 {
+			return window(lambda - shift)
+		}
+	}
+	return &HalfCosine{Filter: newBank(graph, method, lmax, kernels), Nf: nf}, nil
+}
+
+// Simoncelli is the two-filter tight frame of Simoncelli: a low-pass kernel and its power complement.
+type Simoncelli struct {
+	*Filter
+	// A sets the transition band, [A, 2A] on the spectrum rescaled to [0, 2]
+	A float64
+}
+
+// NewSimoncelli returns the Simoncelli low-pass and high-pass pair, PyGSP uses a = 2/3.
+func NewSimoncelli(graph *graphs.Graph, method Method, a float64) (*Simoncelli, error) {
+	filter, err := newTightPair(graph, method, a, func(x float64) float64 {
+		return math.Cos(math.Pi / 2 * math.Log(x/a) / math.Ln2)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Simoncelli{Filter: filter, A: a}, nil
+}
+
+// Held is the two-filter tight frame of Held: a low-pass kernel and its power complement.
+type Held struct {
+	*Filter
+	// A sets the transition band, [A, 2A] on the spectrum rescaled to [0, 2]
+	A float64
+}
+
+// NewHeld returns the Held low-pass and high-pass pair, PyGSP uses a = 2/3.
+func NewHeld(graph *graphs.Graph, method Method, a float64) (*Held, error) {
+	mu := func(x float64) float64 {
+		return -1 + 24*x - 144*x*x + 256*x*x*x
+	}
+	filter, err := newTightPair(graph, method, a, func(x float64) float64 {
+		return math.Sin(2 * math.Pi * mu(x/(8*a)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Held{Filter: filter, A: a}, nil
+}
+
+// Papadimitriou is the two-filter tight frame of Papadimitriou: a low-pass kernel and its power complement.
+type Papadimitriou struct {
+	*Filter
+	// A sets the transition band, [A, 2A] on the spectrum rescaled to [0, 2]
+	A float64
+}
+
+// NewPapadimitriou returns the Papadimitriou low-pass and high-pass pair, PyGSP uses a = 2/3.
+func NewPapadimitriou(graph *graphs.Graph, method Method, a float64) (*Papadimitriou, error) {
+	filter, err := newTightPair(graph, method, a, func(x float64) float64 {
+		return math.Sqrt((1 - math.Sin(3*math.Pi/(2*a)*x)) / 2)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Papadimitriou{Filter: filter, A: a}, nil
+}
+
+// newTightPair builds the low-pass kernel that is 1 below a, follows the transition on [a, 2a] and is 0 above,
+// on the spectrum rescaled to [0, 2], together with its power complement sqrt(1 - low²).
+func newTightPair(graph *graphs.Graph, method Method, a float64, transition func(float64) float64) (*Filter, error) {
+	if a <= 0 || a >= 1 {
+		return nil, fmt.Errorf("transition parameter must lie in (0, 1), got %g", a)
+	}
+	lmax, err := bankLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	low := func(lambda float64) float64 {
+		x := lambda * 2 / lmax
+		switch {
+		case x < a:
+			return 1
+		case x < 2*a:
+			return transition(x)
+		}
+		return 0
+	}
+	high := func(lambda float64) float64 {
+		g := low(lambda)
+		return math.Sqrt(math.Max(0, 1-g*g))
+	}
+	return newBank(graph, method, lmax, []Kernel{low, high}), nil
+}
+
+// kernelNorm returns the Euclidean norm of the kernel's response at the eigenvalues.
+func kernelNorm(kernel Kernel, eigenvalues []float64) float64 {
+	sum := 0.0
+	for _, lambda := range eigenvalues {
+		g := kernel(lambda)
+		sum += g * g
+	}
+	if sum == 0 {
+		return 1
+	}
+	return math.Sqrt(sum)
+}
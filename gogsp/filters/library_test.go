@@ -0,0 +1,207 @@
+package filters
+
+import (
+	"example/gogsp/graphs"
+	"math"
+	"testing"
+)
+
+// spectrumGrid returns n+1 points spread uniformly over [0, lmax].
+func spectrumGrid(lmax float64, n int) []float64 {
+	grid := make([]float64, n+1)
+	for i := range grid {
+		grid[i] = lmax * float64(i) / float64(n)
+	}
+	return grid
+}
+
+func TestLibraryValues(t *testing.T) {
+	g, err := graphs.Path(20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lmax := basis.LMax
+
+	heat, err := NewHeat(g, MethodExact, []float64{10}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mexican, err := NewMexicanHat(g, MethodExact, 4, 20, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meyer, err := NewMeyer(g, MethodExact, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	held, err := NewHeld(g, MethodExact, 2./3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		kernel Kernel
+		lambda float64
+		want   float64
+	}{
+		{"heat at 0", heat.Kernels[0], 0, 1},
+		{"heat at lmax", heat.Kernels[0], lmax, math.Exp(-10)},
+		{"mexican hat low-pass at 0", mexican.Kernels[0], 0, 1.2 * math.Exp(-1)},
+		{"mexican hat at 1/t", mexican.Kernels[1], 1 / mexican.Scales[0], math.Exp(-1)},
+		{"meyer scaling at 0", meyer.Kernels[0], 0, 1},
+		{"meyer scaling midway", meyer.Kernels[0], 1 / meyer.Scales[0], math.Cos(math.Pi / 4)},
+		{"meyer coarsest wavelet midway", meyer.Kernels[1], 1 / meyer.Scales[0], math.Sin(math.Pi / 4)},
+		{"meyer finest wavelet peaking at lmax", meyer.Kernels[2], lmax, 1},
+		{"held low-pass below a", held.Kernels[0], lmax / 4, 1},
+		{"held low-pass above 2a", held.Kernels[0], lmax * 0.7, 0},
+		{"held high-pass at lmax", held.Kernels[1], lmax, 1},
+	}
+	for _, test := range tests {
+		if got := test.kernel(test.lambda); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("%s: got %g, want %g", test.name, got, test.want)
+		}
+	}
+	if meyerTransition(0) != 0 || meyerTransition(0.5) != 0.5 || meyerTransition(1) != 1 {
+		t.Errorf("got v(0), v(0.5), v(1) = %g, %g, %g, want 0, 0.5, 1",
+			meyerTransition(0), meyerTransition(0.5), meyerTransition(1))
+	}
+}
+
+func TestLibraryTightFrames(t *testing.T) {
+	g, err := graphs.Ring(24, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	build := map[string]func() (*Filter, error){
+		"meyer": func() (*Filter, error) {
+			f, err := NewMeyer(g, MethodExact, 5)
+			if err != nil {
+				return nil, err
+			}
+			return f.Filter, nil
+		},
+		"itersine": func() (*Filter, error) {
+			f, err := NewItersine(g, MethodExact, 6, 2)
+			if err != nil {
+				return nil, err
+			}
+			return f.Filter, nil
+		},
+		"simoncelli": func() (*Filter, error) {
+			f, err := NewSimoncelli(g, MethodExact, 2./3)
+			if err != nil {
+				return nil, err
+			}
+			return f.Filter, nil
+		},
+		"held": func() (*Filter, error) {
+			f, err := NewHeld(g, MethodExact, 2./3)
+			if err != nil {
+				return nil, err
+			}
+			return f.Filter, nil
+		},
+		"papadimitriou": func() (*Filter, error) {
+			f, err := NewPapadimitriou(g, MethodExact, 2./3)
+			if err != nil {
+				return nil, err
+			}
+			return f.Filter, nil
+		},
+	}
+	for name, newFilter := range build {
+		filter, err := newFilter()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// sum_k g_k(λ)² is the same everywhere on the spectrum, not only at the eigenvalues
+		var first float64
+		for i, lambda := range spectrumGrid(filter.LMax, 1000) {
+			sum := 0.0
+			for _, kernel := range filter.Kernels {
+				sum += kernel(lambda) * kernel(lambda)
+			}
+			if i == 0 {
+				first = sum
+			} else if math.Abs(sum-first) > 1e-10 {
+				t.Errorf("%s: sum of squares %g at %g, %g at 0", name, sum, lambda, first)
+				break
+			}
+		}
+		bounds, err := filter.FrameBounds()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bounds.IsTight(1e-10) {
+			t.Errorf("%s: got bounds [%g, %g], want a tight frame", name, bounds.Lower, bounds.Upper)
+		}
+	}
+}
+
+func TestLibraryUniform(t *testing.T) {
+	g, err := graphs.Ring(24, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank, err := NewHalfCosine(g, MethodExact, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Three raised cosines overlap everywhere on the spectrum and sum to 3/2
+	for _, lambda := range spectrumGrid(bank.LMax, 1000) {
+		sum := 0.0
+		for _, kernel := range bank.Kernels {
+			sum += kernel(lambda)
+		}
+		if math.Abs(sum-1.5) > 1e-10 {
+			t.Fatalf("the kernels sum to %g at %g, want 1.5", sum, lambda)
+		}
+	}
+}
+
+func TestHeatNormalize(t *testing.T) {
+	g, err := graphs.Path(20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heat, err := NewHeat(g, MethodExact, []float64{1, 5}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, kernel := range heat.Kernels {
+		if norm := kernelNorm(kernel, basis.Eigenvalues); math.Abs(norm-1) > 1e-12 {
+			t.Errorf("kernel %d has norm %g, want 1", k, norm)
+		}
+	}
+}
+
+func TestLibraryErrors(t *testing.T) {
+	g, err := graphs.Path(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := graphs.NewGraph()
+	empty.AddNode(1)
+	tests := map[string]error{}
+	_, tests["heat without scales"] = NewHeat(g, MethodExact, nil, false)
+	_, tests["heat without edges"] = NewHeat(empty, MethodExact, []float64{1}, false)
+	_, tests["mexican hat of 1 filter"] = NewMexicanHat(g, MethodExact, 1, 20, false)
+	_, tests["abspline of 1 filter"] = NewAbspline(g, MethodExact, 1, 20)
+	_, tests["meyer of 1 filter"] = NewMeyer(g, MethodExact, 1)
+	_, tests["itersine with overlap 0"] = NewItersine(g, MethodExact, 4, 0)
+	_, tests["half cosine of 2 filters"] = NewHalfCosine(g, MethodExact, 2)
+	_, tests["simoncelli with a = 1"] = NewSimoncelli(g, MethodExact, 1)
+	for name, err := range tests {
+		if err == nil {
+			t.Errorf("%s is built", name)
+		}
+	}
+}
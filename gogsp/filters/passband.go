@@ -0,0 +1,209 @@
+// passband.go contains the frequency-selective filters: low-pass, high-pass, band-pass and band-stop.
+// They are defined on the Laplacian eigenvalues, with an ideal (brick-wall), Butterworth or heat-kernel response,
+// and a cutoff given either as an eigenvalue or as a fraction of the largest eigenvalue lmax.
+// Every high-pass response is the power complement of the matching low-pass one, |low(λ)|² + |high(λ)|² = 1,
+// and the same holds for band-stop and band-pass, so each pair splits a signal without losing energy.
+
+package filters
+
+import (
+	"example/gogsp/graphs"
+	"fmt"
+	"math"
+)
+
+// Shape is the shape of a frequency-selective response.
+type Shape int
+
+const (
+	// ShapeIdeal passes the band with gain 1 and removes everything else
+	ShapeIdeal Shape = iota
+	// ShapeButterworth is maximally flat in the pass band, with a roll-off set by Response.Order
+	ShapeButterworth
+	// ShapeHeat decays like the heat kernel exp(-τλ)
+	ShapeHeat
+)
+
+// Response describes the shape of a frequency-selective filter. All shapes have a gain of 1/√2 at the cutoff,
+// except the ideal one which switches from 1 to 0 just above it.
+type Response struct {
+	Shape Shape
+	// Order is the order n of a Butterworth response, |g(λ)|² = 1 / (1 + (λ/cutoff)^2n)
+	Order int
+}
+
+// Ideal returns a brick-wall response.
+func Ideal() Response {
+	return Response{Shape: ShapeIdeal}
+}
+
+// Butterworth returns a Butterworth response of the given order.
+func Butterworth(order int) Response {
+	return Response{Shape: ShapeButterworth, Order: order}
+}
+
+// HeatDecay returns a heat-kernel response, g(λ) = exp(-τλ) with τ chosen so that g(cutoff) = 1/√2.
+func HeatDecay() Response {
+	return Response{Shape: ShapeHeat}
+}
+
+// lowPass returns the low-pass gain of the response at λ for a cutoff c.
+func (r Response) lowPass(lambda, c float64) float64 {
+	switch r.Shape {
+	case ShapeButterworth:
+		return 1 / math.Sqrt(1+math.Pow(lambda/c, float64(2*r.Order)))
+	case ShapeHeat:
+		return math.Exp(-math.Ln2 / 2 * lambda / c)
+	}
+	return indicator(lambda <= c)
+}
+
+// highPass returns the power complement of the low-pass gain.
+func (r Response) highPass(lambda, c float64) float64 {
+	low := r.lowPass(lambda, c)
+	return math.Sqrt(math.Max(0, 1-low*low))
+}
+
+func (r Response) validate() error {
+	switch r.Shape {
+	case ShapeIdeal, ShapeHeat:
+		return nil
+	case ShapeButterworth:
+		if r.Order < 1 {
+			return fmt.Errorf("butterworth order must be at least 1, got %d", r.Order)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown response shape %d", r.Shape)
+}
+
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Cutoff is a graph frequency, given either as a Laplacian eigenvalue or as a fraction of lmax.
+type Cutoff struct {
+	value    float64
+	relative bool
+}
+
+// Lambda returns a cutoff at the eigenvalue λ.
+func Lambda(lambda float64) Cutoff {
+	return Cutoff{value: lambda}
+}
+
+// FractionOfLMax returns a cutoff at the given fraction of the largest Laplacian eigenvalue.
+func FractionOfLMax(fraction float64) Cutoff {
+	return Cutoff{value: fraction, relative: true}
+}
+
+// Resolve returns the cutoff as an eigenvalue for a spectrum ending at lmax.
+func (c Cutoff) Resolve(lmax float64) float64 {
+	if c.relative {
+		return c.value * lmax
+	}
+	return c.value
+}
+
+func (c Cutoff) String() string {
+	if c.relative {
+		return fmt.Sprintf("%g lmax", c.value)
+	}
+	return fmt.Sprintf("λ = %g", c.value)
+}
+
+// resolveCutoffs turns the cutoffs into eigenvalues and returns lmax as well. The approximate methods always need
+// lmax, which EstimateLMax gives once here rather than on every filtered signal, and would lose their advantage if
+// building the filter cost a full eigendecomposition. MethodExact only fetches it from the eigendecomposition when a
+// cutoff is relative.
+func resolveCutoffs(graph *graphs.Graph, method Method, cutoffs ...Cutoff) ([]float64, float64, error) {
+	needed := method != MethodExact
+	for _, c := range cutoffs {
+		needed = needed || c.relative
+	}
+	lmax := 0.0
+	if needed {
+		var err error
+		if lmax, err = spectrumLMax(graph, method); err != nil {
+			return nil, 0, err
+		}
+	}
+	resolved := make([]float64, len(cutoffs))
+	for i, c := range cutoffs {
+		resolved[i] = c.Resolve(lmax)
+		if resolved[i] <= 0 {
+			return nil, 0, fmt.Errorf("cutoff %v must be a positive graph frequency", c)
+		}
+	}
+	return resolved, lmax, nil
+}
+
+// NewLowPass returns a filter applied with the given method that keeps the graph frequencies below the cutoff.
+func NewLowPass(graph *graphs.Graph, method Method, response Response, cutoff Cutoff) (*Filter, error) {
+	if err := response.validate(); err != nil {
+		return nil, err
+	}
+	c, lmax, err := resolveCutoffs(graph, method, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return newBank(graph, method, lmax, []Kernel{func(lambda float64) float64 {
+		return response.lowPass(lambda, c[0])
+	}}), nil
+}
+
+// NewHighPass returns a filter applied with the given method that keeps the graph frequencies above the cutoff.
+func NewHighPass(graph *graphs.Graph, method Method, response Response, cutoff Cutoff) (*Filter, error) {
+	if err := response.validate(); err != nil {
+		return nil, err
+	}
+	c, lmax, err := resolveCutoffs(graph, method, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return newBank(graph, method, lmax, []Kernel{func(lambda float64) float64 {
+		return response.highPass(lambda, c[0])
+	}}), nil
+}
+
+// NewBandPass returns a filter applied with the given method that keeps the graph frequencies between the low and
+// high cutoffs.
+func NewBandPass(graph *graphs.Graph, method Method, response Response, low, high Cutoff) (*Filter, error) {
+	kernel, lmax, err := bandPass(graph, method, response, low, high)
+	if err != nil {
+		return nil, err
+	}
+	return newBank(graph, method, lmax, []Kernel{kernel}), nil
+}
+
+// NewBandStop returns a filter applied with the given method that removes the graph frequencies between the low and
+// high cutoffs.
+func NewBandStop(graph *graphs.Graph, method Method, response Response, low, high Cutoff) (*Filter, error) {
+	kernel, lmax, err := bandPass(graph, method, response, low, high)
+	if err != nil {
+		return nil, err
+	}
+	return newBank(graph, method, lmax, []Kernel{func(lambda float64) float64 {
+		pass := kernel(lambda)
+		return math.Sqrt(math.Max(0, 1-pass*pass))
+	}}), nil
+}
+
+func bandPass(graph *graphs.Graph, method Method, response Response, low, high Cutoff) (Kernel, float64, error) {
+	if err := response.validate(); err != nil {
+		return nil, 0, err
+	}
+	c, lmax, err := resolveCutoffs(graph, method, low, high)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c[0] >= c[1] {
+		return nil, 0, fmt.Errorf("band cutoffs must be increasing, got %v and %v", low, high)
+	}
+	return func(lambda float64) float64 {
+		return response.highPass(lambda, c[0]) * response.lowPass(lambda, c[1])
+	}, lmax, nil
+}
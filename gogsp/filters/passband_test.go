@@ -0,0 +1,217 @@
+package filters
+
+import (
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// region tells whether a graph frequency lies in the pass band, in the stop band or in a transition band.
+type region int
+
+const (
+	transition region = iota
+	pass
+	stop
+)
+
+type passbandCase struct {
+	name   string
+	build  func(g *graphs.Graph, method Method, r Response) (*Filter, error)
+	region func(lambda, lmax float64) region
+}
+
+// around returns the region of λ for a response that changes at the cutoffs, with a transition band of a factor
+// 1.5 on either side of each.
+func around(lambda float64, inside bool, cutoffs ...float64) region {
+	for _, c := range cutoffs {
+		if lambda > c/1.5 && lambda < c*1.5 {
+			return transition
+		}
+	}
+	if inside {
+		return pass
+	}
+	return stop
+}
+
+var passbandCases = []passbandCase{
+	{
+		name: "low-pass",
+		build: func(g *graphs.Graph, method Method, r Response) (*Filter, error) {
+			return NewLowPass(g, method, r, FractionOfLMax(0.4))
+		},
+		region: func(lambda, lmax float64) region {
+			return around(lambda, lambda < 0.4*lmax, 0.4*lmax)
+		},
+	},
+	{
+		name: "high-pass",
+		build: func(g *graphs.Graph, method Method, r Response) (*Filter, error) {
+			return NewHighPass(g, method, r, FractionOfLMax(0.4))
+		},
+		region: func(lambda, lmax float64) region {
+			return around(lambda, lambda > 0.4*lmax, 0.4*lmax)
+		},
+	},
+	{
+		name: "band-pass",
+		build: func(g *graphs.Graph, method Method, r Response) (*Filter, error) {
+			return NewBandPass(g, method, r, FractionOfLMax(0.15), FractionOfLMax(0.6))
+		},
+		region: func(lambda, lmax float64) region {
+			return around(lambda, lambda > 0.15*lmax && lambda < 0.6*lmax, 0.15*lmax, 0.6*lmax)
+		},
+	},
+	{
+		name: "band-stop",
+		build: func(g *graphs.Graph, method Method, r Response) (*Filter, error) {
+			return NewBandStop(g, method, r, FractionOfLMax(0.15), FractionOfLMax(0.6))
+		},
+		region: func(lambda, lmax float64) region {
+			return around(lambda, lambda < 0.15*lmax || lambda > 0.6*lmax, 0.15*lmax, 0.6*lmax)
+		},
+	},
+}
+
+// checkEigencomponents feeds every eigenvector of the graph through the filter. The output must be the same
+// eigenvector scaled by the gain of the kernel, which must pass the pass band and attenuate the stop band.
+func checkEigencomponents(t *testing.T, g *graphs.Graph, filter *Filter, c passbandCase, tolerance float64) {
+	t.Helper()
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, lambda := range basis.Eigenvalues {
+		u := signals.Signal(mat.Col(nil, k, basis.Eigenvectors))
+		out, err := filter.Apply(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := filter.Kernels[0](lambda)
+		gain, leak := 0.0, 0.0
+		for i := range u {
+			gain += u[i] * out[i]
+		}
+		for i := range u {
+			leak = math.Max(leak, math.Abs(out[i]-gain*u[i]))
+		}
+		if math.Abs(gain-want) > tolerance || leak > tolerance {
+			t.Errorf("%s: λ = %.4f has gain %.6f and leaks %.2g to other modes, want gain %.6f", c.name, lambda, gain, leak, want)
+		}
+		switch c.region(lambda, basis.LMax) {
+		case pass:
+			if gain < 0.9 {
+				t.Errorf("%s: λ = %.4f is in the pass band but has gain %.4f", c.name, lambda, gain)
+			}
+		case stop:
+			if gain > 0.1 {
+				t.Errorf("%s: λ = %.4f is in the stop band but has gain %.4f", c.name, lambda, gain)
+			}
+		}
+	}
+}
+
+func TestPassbandExact(t *testing.T) {
+	g, err := graphs.Path(30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range passbandCases {
+		for _, r := range []Response{Ideal(), Butterworth(6)} {
+			filter, err := c.build(g, MethodExact, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEigencomponents(t, g, filter, c, 1e-10)
+		}
+	}
+}
+
+func TestPassbandChebyshev(t *testing.T) {
+	g, err := graphs.Path(30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range passbandCases {
+		filter, err := c.build(g, MethodChebyshev, Butterworth(6))
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter.Order = 150
+		checkEigencomponents(t, g, filter, c, 1e-2)
+
+		basis, err := g.Basis()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filter.LMax < basis.LMax {
+			t.Errorf("%s: the Chebyshev interval [0, %g] misses the top of the spectrum %g", c.name, filter.LMax, basis.LMax)
+		}
+	}
+}
+
+func TestPassbandCutoffs(t *testing.T) {
+	g, err := graphs.Path(30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewBandPass(g, MethodExact, Ideal(), Lambda(2), Lambda(1)); err == nil {
+		t.Error("decreasing band cutoffs are accepted")
+	}
+	if _, err := NewLowPass(g, MethodExact, Ideal(), Lambda(0)); err == nil {
+		t.Error("a zero cutoff is accepted")
+	}
+	if _, err := NewLowPass(g, MethodExact, Butterworth(0), Lambda(1)); err == nil {
+		t.Error("a Butterworth response of order 0 is accepted")
+	}
+
+	// Approximate filters with absolute cutoffs fix lmax once instead of estimating it on every signal
+	for _, build := range []func() (*Filter, error){
+		func() (*Filter, error) { return NewLowPass(g, MethodChebyshev, Ideal(), Lambda(1)) },
+		func() (*Filter, error) { return NewBandStop(g, MethodChebyshev, Ideal(), Lambda(1), Lambda(2)) },
+	} {
+		filter, err := build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filter.LMax <= 0 {
+			t.Errorf("got lmax %g after construction, want a positive bound", filter.LMax)
+		}
+	}
+}
+
+func TestPassFilterFuncs(t *testing.T) {
+	g, err := graphs.Path(20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := randomSignal(20, 1)
+	for _, c := range []struct {
+		name   string
+		filter FilterFunc
+		build  func(*graphs.Graph, Method, Response, Cutoff) (*Filter, error)
+	}{
+		{"LowPassFilter", LowPassFilter, NewLowPass},
+		{"HighPassFilter", HighPassFilter, NewHighPass},
+	} {
+		got, err := ApplyFilter(c.filter, g, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter, err := c.build(g, MethodExact, Butterworth(2), FractionOfLMax(0.5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := filter.Apply(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := relativeError(want, got); e > 1e-12 {
+			t.Errorf("%s is off by %g", c.name, e)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// sgwt.go contains the spectral graph wavelet transform (SGWT) of Hammond, Vandergheynst and Gribonval,
+// "Wavelets on graphs via spectral graph theory", 2011.
+// The transform is a filter bank made of one scaling function h(λ), which captures the lowest frequencies, and J
+// band-pass wavelet kernels g(t_j λ) at scales t_j spaced logarithmically between the ends of the spectrum.
+
+package filters
+
+import (
+	"errors"
+	"example/gogsp/core"
+	"example/gogsp/graphs"
+	"fmt"
+	"math"
+)
+
+// SGWTLowPassFactor is the ratio lmax / lmin that sets the lowest frequency resolved by the wavelets.
+// Frequencies below lmin are covered by the scaling function.
+const SGWTLowPassFactor = 20
+
+// SGWTKernel is the wavelet generating kernel of Hammond et al. with α = β = 2, x1 = 1 and x2 = 2:
+// it rises as x² up to 1, falls as 4/x² from 2, and is joined by the cubic spline -5 + 11x - 6x² + x³ in between.
+func SGWTKernel(x float64) float64 {
+	switch {
+	case x < 1:
+		return x * x
+	case x <= 2:
+		return -5 + 11*x - 6*x*x + x*x*x
+	default:
+		return 4 / (x * x)
+	}
+}
+
+// SGWT is a spectral graph wavelet transform on a graph.
+// Column 0 of the analysis coefficients holds the scaling function output, column j holds wavelet scale j.
+// Coefficients are turned back into a signal with Reconstruct, and FrameBounds tells how well conditioned that is.
+type SGWT struct {
+	*Filter
+	// Scales holds the J wavelet scales t_1 > ... > t_J, from the coarsest to the finest
+	Scales []float64
+}
+
+// NewSGWT builds the transform with J wavelet scales. The method decides how the filter bank is applied;
+// MethodExact takes lmax from the eigendecomposition, the approximate methods use EstimateLMax.
+func NewSGWT(graph *graphs.Graph, j int, method Method) (*SGWT, error) {
+	if j < 1 {
+		return nil, fmt.Errorf("the wavelet transform needs at least one scale, got %d", j)
+	}
+
+	lmax, err := spectrumLMax(graph, method)
+	if err != nil {
+		return nil, err
+	}
+	if lmax <= 0 {
+		return nil, errors.New("the wavelet transform needs a graph with at least one edge")
+	}
+	lmin := lmax / SGWTLowPassFactor
+	scales := logScales(lmin, lmax, j)
+
+	kernels := make([]Kernel, 0, j+1)
+	kernels = append(kernels, sgwtScaling(lmin))
+	for _, t := range scales {
+		t := t
+		kernels = append(kernels, func(lambda float64) float64 {
+			return SGWTKernel(t * lambda)
+		})
+	}
+
+	filter := NewFilter(graph, kernels...)
+	filter.Method = method
+	filter.LMax = lmax
+	return &SGWT{Filter: filter, Scales: scales}, nil
+}
+
+// logScales returns n scales spaced logarithmically from x2/lmin down to x1/lmax with x1 = 1 and x2 = 2, so that
+// the coarsest wavelet reaches down to lmin and the finest up to lmax.
+func logScales(lmin, lmax float64, n int) []float64 {
+	scales := make([]float64, n)
+	smax, smin := 2/lmin, 1/lmax
+	for i := range scales {
+		if n == 1 {
+			scales[i] = smax
+			continue
+		}
+		scales[i] = math.Exp(math.Log(smax) + float64(i)*(math.Log(smin)-math.Log(smax))/float64(n-1))
+	}
+	return scales
+}
+
+// spectrumLMax returns the lmax a filter bank is designed for: the exact one for MethodExact, and the cheaper
+// EstimateLMax for the approximate methods, which must never be given a spectrum wider than their interval.
+func spectrumLMax(graph *graphs.Graph, method Method) (float64, error) {
+	if method == MethodExact {
+		basis, err := graph.Basis()
+		if err != nil {
+			return 0, err
+		}
+		return basis.LMax, nil
+	}
+	return EstimateLMax(graph, core.LanczosOptions{}), nil
+}
+
+// sgwtScaling returns the scaling function h(λ) = γ exp(-(λ / 0.6 lmin)^4), where γ is the peak of the wavelet
+// kernel so that h and the wavelets have comparable magnitudes.
+func sgwtScaling(lmin float64) Kernel {
+	// The peak of SGWTKernel lies in the spline part, on [1, 2]
+	gamma := 0.0
+	for x := 1.0; x <= 2; x += 1e-3 {
+		gamma = math.Max(gamma, SGWTKernel(x))
+	}
+	return func(lambda float64) float64 {
+		return gamma * math.Exp(-math.Pow(lambda/(0.6*lmin), 4))
+	}
+}
@@ -0,0 +1,98 @@
+package filters
+
+import (
+	"example/gogsp/graphs"
+	"math"
+	"testing"
+)
+
+func TestSGWTKernel(t *testing.T) {
+	// The spline joins x² at 1 and 4/x² at 2 with matching values and slopes
+	const h = 1e-7
+	for _, x := range []float64{1, 2} {
+		left, right := SGWTKernel(x-h), SGWTKernel(x+h)
+		if math.Abs(left-right) > 1e-6 {
+			t.Errorf("the kernel jumps from %g to %g at %g", left, right, x)
+		}
+		slopeLeft := (SGWTKernel(x-h) - SGWTKernel(x-2*h)) / h
+		slopeRight := (SGWTKernel(x+2*h) - SGWTKernel(x+h)) / h
+		if math.Abs(slopeLeft-slopeRight) > 1e-5 {
+			t.Errorf("the slope jumps from %g to %g at %g", slopeLeft, slopeRight, x)
+		}
+	}
+	if SGWTKernel(0) != 0 || SGWTKernel(4) != 0.25 {
+		t.Errorf("got g(0) = %g and g(4) = %g, want 0 and 0.25", SGWTKernel(0), SGWTKernel(4))
+	}
+}
+
+func TestLogScales(t *testing.T) {
+	const lmin, lmax = 0.1, 4.0
+	scales := logScales(lmin, lmax, 5)
+	if math.Abs(scales[0]-2/lmin) > 1e-12 || math.Abs(scales[4]-1/lmax) > 1e-12 {
+		t.Errorf("got scales from %g to %g, want from %g to %g", scales[0], scales[4], 2/lmin, 1/lmax)
+	}
+	// Consecutive scales have the same ratio
+	ratio := scales[1] / scales[0]
+	for i := 1; i < len(scales); i++ {
+		if r := scales[i] / scales[i-1]; math.Abs(r-ratio) > 1e-12 {
+			t.Errorf("scale %d is %g times the previous one, want %g", i, r, ratio)
+		}
+	}
+	if one := logScales(lmin, lmax, 1); len(one) != 1 || one[0] != 2/lmin {
+		t.Errorf("got the single scale %v, want %g", one, 2/lmin)
+	}
+}
+
+func TestSGWTScaling(t *testing.T) {
+	const lmin = 0.5
+	h := sgwtScaling(lmin)
+	// γ is the peak of the kernel, which a finer search does not improve on
+	peak := 0.0
+	for x := 1.0; x <= 2; x += 1e-6 {
+		peak = math.Max(peak, SGWTKernel(x))
+	}
+	if gamma := h(0); math.Abs(gamma-peak) > 1e-6 {
+		t.Errorf("got γ = %g, want the peak %g", gamma, peak)
+	}
+	if want := h(0) * math.Exp(-1); math.Abs(h(0.6*lmin)-want) > 1e-12 {
+		t.Errorf("got h(0.6 lmin) = %g, want γ/e = %g", h(0.6*lmin), want)
+	}
+}
+
+func TestNewSGWT(t *testing.T) {
+	g, err := graphs.Path(30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sgwt, err := NewSGWT(g, 4, MethodExact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sgwt.Scales) != 4 || len(sgwt.Kernels) != 5 {
+		t.Errorf("got %d scales and %d kernels, want 4 and 5", len(sgwt.Scales), len(sgwt.Kernels))
+	}
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sgwt.LMax != basis.LMax {
+		t.Errorf("got lmax %g, want %g", sgwt.LMax, basis.LMax)
+	}
+	bounds, err := sgwt.FrameBounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bounds.IsFrame() {
+		t.Errorf("got bounds [%g, %g], want a frame", bounds.Lower, bounds.Upper)
+	}
+
+	if _, err := NewSGWT(g, 0, MethodExact); err == nil {
+		t.Error("a transform without scales is built")
+	}
+	empty := graphs.NewGraph()
+	empty.AddNode(1)
+	empty.AddNode(2)
+	if _, err := NewSGWT(empty, 3, MethodExact); err == nil {
+		t.Error("a transform is built on a graph without edges")
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"example/gogsp/graphs"
+	"example/gogsp/signals"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// graphFormat reads and writes one graph file format.
+type graphFormat struct {
+	read  func(io.Reader, ...graphs.ReadOption) (*graphs.Graph, error)
+	write func(io.Writer, *graphs.Graph) error
+}
+
+// graphFormats are the graph formats by name; adjacency is the dense CSV matrix.
+var graphFormats = map[string]graphFormat{
+	"edgelist":  {graphs.ReadEdgeList, graphs.WriteEdgeList},
+	"csv":       {graphs.ReadEdgeCSV, graphs.WriteEdgeCSV},
+	"adjacency": {graphs.ReadAdjacencyCSV, graphs.WriteAdjacencyCSV},
+	"mtx":       {graphs.ReadMatrixMarket, graphs.WriteMatrixMarket},
+	"graphml":   {graphs.ReadGraphML, graphs.WriteGraphML},
+	"gml":       {graphs.ReadGML, graphs.WriteGML},
+	"dot":       {nil, func(w io.Writer, g *graphs.Graph) error { return graphs.WriteDOT(w, g) }},
+}
+
+// graphExtensions maps file extensions to graph formats.
+var graphExtensions = map[string]string{
+	".txt":      "edgelist",
+	".edges":    "edgelist",
+	".edgelist": "edgelist",
+	".el":       "edgelist",
+	".csv":      "csv",
+	".mtx":      "mtx",
+	".graphml":  "graphml",
+	".xml":      "graphml",
+	".gml":      "gml",
+	".dot":      "dot",
+	".gv":       "dot",
+}
+
+// formatOf returns the format given by name, or else the one of the file extension.
+func formatOf(path, name string, extensions map[string]string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if format, ok := extensions[ext]; ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %q from its extension, give it with a format flag", path)
+}
+
+// loadGraph reads a graph file and sets the Laplacian kind.
+func loadGraph(path string, o *graphFlags) (*graphs.Graph, error) {
+	name, err := formatOf(path, o.format, graphExtensions)
+	if err != nil {
+		return nil, err
+	}
+	format, ok := graphFormats[name]
+	if !ok || format.read == nil {
+		return nil, fmt.Errorf("cannot read graphs in the %q format", name)
+	}
+	laplacian, err := parseLaplacian(o.laplacian)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var opts []graphs.ReadOption
+	if o.directed {
+		opts = append(opts, graphs.WithReadGraphOptions(graphs.Directed(graphs.OutDegree)))
+	}
+	g, err := format.read(f, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	g.SetLaplacianKind(laplacian)
+	return g, nil
+}
+
+// saveGraph writes a graph file, or to standard output if path is "-".
+func saveGraph(path, format string, g *graphs.Graph) error {
+	name := format
+	if name == "" && path == "-" {
+		name = "edgelist"
+	}
+	name, err := formatOf(path, name, graphExtensions)
+	if err != nil {
+		return err
+	}
+	f, ok := graphFormats[name]
+	if !ok {
+		return fmt.Errorf("cannot write graphs in the %q format", name)
+	}
+	return create(path, func(w io.Writer) error {
+		return f.write(w, g)
+	})
+}
+
+func parseLaplacian(name string) (graphs.LaplacianKind, error) {
+	for _, kind := range []graphs.LaplacianKind{graphs.Combinatorial, graphs.Normalized, graphs.RandomWalk, graphs.Signless} {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown Laplacian %q, expected combinatorial, normalized, random-walk or signless", name)
+}
+
+// signalExtensions maps file extensions to signal formats.
+var signalExtensions = map[string]string{
+	".csv":  "csv",
+	".json": "json",
+	".npy":  "npy",
+	".npz":  "npz",
+}
+
+// loadSignals reads a file of signals on the graph as an N x T matrix. Of a .npz file it reads the given array, or
+// the only one.
+func loadSignals(path string, o *signalFlags, g *graphs.Graph) (*mat.Dense, error) {
+	name, err := formatOf(path, o.format, signalExtensions)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var m *mat.Dense
+	switch name {
+	case "csv":
+		m, err = signals.ReadCSV(f, g)
+	case "json":
+		m, err = signals.ReadJSON(f, g)
+	case "npy":
+		m, err = signals.ReadNPY(f, g)
+	case "npz":
+		m, err = loadArray(f, o.array, g)
+	default:
+		return nil, fmt.Errorf("cannot read signals in the %q format", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+func loadArray(f *os.File, array string, g *graphs.Graph) (*mat.Dense, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	arrays, err := signals.ReadNPZ(f, info.Size(), g)
+	if err != nil {
+		return nil, err
+	}
+	if array != "" {
+		m, ok := arrays[array]
+		if !ok {
+			return nil, fmt.Errorf("no array %q", array)
+		}
+		return m, nil
+	}
+	if len(arrays) != 1 {
+		names := make([]string, 0, len(arrays))
+		for name := range arrays {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("the file holds the arrays %s, choose one with -array", strings.Join(names, ", "))
+	}
+	for _, m := range arrays {
+		return m, nil
+	}
+	return nil, nil
+}
+
+// saveSignals writes an N x T matrix of signals, as CSV to standard output if path is "-". A .npz file gets a
+// single array with the given name.
+func saveSignals(path, format string, g *graphs.Graph, m mat.Matrix, array string) error {
+	name := format
+	if name == "" && path == "-" {
+		name = "csv"
+	}
+	name, err := formatOf(path, name, signalExtensions)
+	if err != nil {
+		return err
+	}
+	return create(path, func(w io.Writer) error {
+		switch name {
+		case "csv":
+			return signals.WriteCSV(w, g, m)
+		case "json":
+			return signals.WriteJSON(w, g, m)
+		case "npy":
+			return signals.WriteNPY(w, g, m)
+		case "npz":
+			return signals.WriteNPZ(w, g, map[string]mat.Matrix{array: m})
+		}
+		return fmt.Errorf("cannot write signals in the %q format", name)
+	})
+}
+
+// create writes a file with fn, or standard output if path is "-". A file that fails to be written is removed.
+func create(path string, fn func(io.Writer) error) error {
+	if path == "-" {
+		return fn(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,155 @@
+// csv.go contains the dense adjacency format: the N x N weight matrix as CSV, where a non-zero entry (i, j) is an
+// edge from node i to node j. An optional header row names the nodes; it may start with an empty cell, in which
+// case every row starts with the name of its node as well, as written by WriteAdjacencyCSV.
+// A header is told apart from the first row of weights by a cell that is not a number, so a header of integer node
+// IDs must start with the empty cell, or it is read as weights. Undirected graphs need a symmetric matrix.
+
+package graphs
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadAdjacencyCSV reads a dense weight matrix. Without a header, row i is node i. A header is recognised by an empty
+// first cell, which names every row as well, or by a node name that is not a number.
+func ReadAdjacencyCSV(r io.Reader, opts ...ReadOption) (*Graph, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows [][]string
+	var lines []int
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &ParseError{Line: parseErr.Line, Err: parseErr.Err}
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, fields)
+		lines = append(lines, line)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("empty adjacency matrix")
+	}
+
+	// A first row with a non-numeric cell, the empty corner cell included, is a header
+	var names []string
+	rowNames := false
+	headerLine := lines[0]
+	for _, cell := range rows[0] {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(cell), 64); err != nil {
+			names = rows[0]
+			break
+		}
+	}
+	if names != nil {
+		if strings.TrimSpace(names[0]) == "" {
+			names, rowNames = names[1:], true
+		}
+		rows, lines = rows[1:], lines[1:]
+	}
+	n := len(rows)
+	if names != nil && len(names) != n {
+		return nil, parseErrorf(headerLine, "header names %d nodes but the matrix has %d rows", len(names), n)
+	}
+
+	b := newBuilder(newReadConfig(opts))
+	nodes := make([]Node, n)
+	if names != nil {
+		resolver := newNodeNames(b.graph, names)
+		for i, name := range names {
+			nodes[i] = resolver.node(strings.TrimSpace(name))
+		}
+	} else {
+		for i := range nodes {
+			nodes[i] = Node(i)
+			b.graph.AddNode(nodes[i])
+		}
+	}
+
+	weights := make([][]Weight, n)
+	for i, row := range rows {
+		if rowNames {
+			if len(row) == 0 || strings.TrimSpace(row[0]) != strings.TrimSpace(names[i]) {
+				return nil, parseErrorf(lines[i], "row %d should start with node %q", i+1, strings.TrimSpace(names[i]))
+			}
+			row = row[1:]
+		}
+		if len(row) != n {
+			return nil, parseErrorf(lines[i], "expected %d weights, got %d", n, len(row))
+		}
+		weights[i] = make([]Weight, n)
+		for j, cell := range row {
+			w, err := parseWeight(lines[i], cell)
+			if err != nil {
+				return nil, err
+			}
+			weights[i][j] = w
+		}
+	}
+
+	for i := range weights {
+		for j, w := range weights[i] {
+			if !b.graph.IsDirected() && weights[j][i] != w {
+				return nil, parseErrorf(lines[maxInt(i, j)], "the matrix of an undirected graph must be symmetric, entries (%d, %d) and (%d, %d) differ", i+1, j+1, j+1, i+1)
+			}
+			if w == 0 || (!b.graph.IsDirected() && j < i) {
+				continue
+			}
+			if err := b.edge(lines[i], nodes[i], nodes[j], w); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.graph, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// WriteAdjacencyCSV writes the weight matrix of the graph, self-loops included, in matrix order, with a header row
+// and a first column holding the node labels. Coordinates and attributes are not written.
+func WriteAdjacencyCSV(w io.Writer, g *Graph) error {
+	nodes := g.Nodes()
+	writer := csv.NewWriter(w)
+	header := make([]string, 0, len(nodes)+1)
+	header = append(header, "")
+	for _, n := range nodes {
+		header = append(header, g.Label(n))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	weights := g.SparseWeights()
+	row := make([]string, len(nodes)+1)
+	for i, n := range nodes {
+		row[0] = g.Label(n)
+		for j := range nodes {
+			row[j+1] = "0"
+		}
+		for k := weights.RowPtr[i]; k < weights.RowPtr[i+1]; k++ {
+			row[weights.ColInd[k]+1] = formatWeight(Weight(weights.Values[k]))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
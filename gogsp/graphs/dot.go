@@ -0,0 +1,131 @@
+// dot.go contains a writer for DOT, the input language of Graphviz, e.g. to render a graph with
+// "neato -n -Tsvg graph.dot" at its coordinates. Nodes can be coloured by a signal on the viridis scale.
+
+package graphs
+
+import (
+	"bufio"
+	"example/gogsp/signals"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// dotConfig holds the settings of WriteDOT.
+type dotConfig struct {
+	signal signals.Signal
+	name   string
+}
+
+// DOTOption configures WriteDOT.
+type DOTOption func(*dotConfig)
+
+// ColorBy fills the nodes with the colour of their signal value, from dark purple for the smallest value to yellow
+// for the largest. The signal is in matrix order, like the transforms, and its values must be finite.
+func ColorBy(signal signals.Signal) DOTOption {
+	return func(c *dotConfig) {
+		c.signal = signal
+	}
+}
+
+// WithDOTName sets the name of the graph, "G" by default.
+func WithDOTName(name string) DOTOption {
+	return func(c *dotConfig) {
+		c.name = name
+	}
+}
+
+// WriteDOT writes the graph in DOT, each undirected edge once. Nodes are named by label, edges carry their weight as
+// the attribute w and nodes with coordinates are pinned at the first two, in points. Attributes are not written.
+// Graphviz reads its own weight attribute as an integer layout priority, so the edge weights are not written there.
+func WriteDOT(w io.Writer, g *Graph, opts ...DOTOption) error {
+	config := &dotConfig{name: "G"}
+	for _, opt := range opts {
+		opt(config)
+	}
+	nodes := g.Nodes()
+	if config.signal != nil {
+		if err := config.signal.CheckSize(g); err != nil {
+			return err
+		}
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, x := range config.signal {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Errorf("the signal has the non-finite value %g at index %d, which has no colour", x, i)
+		}
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+	}
+
+	bw := bufio.NewWriter(w)
+	kind, arrow := "graph", "--"
+	if g.IsDirected() {
+		kind, arrow = "digraph", "->"
+	}
+	fmt.Fprintf(bw, "%s %s {\n", kind, quoteDOT(config.name))
+	if config.signal != nil {
+		fmt.Fprintln(bw, "  node [style=filled];")
+	}
+	for i, n := range nodes {
+		var attributes []string
+		if c, ok := g.Coordinates(n); ok && len(c) >= 2 {
+			attributes = append(attributes, fmt.Sprintf("pos=\"%s,%s!\"", formatWeight(Weight(c[0])), formatWeight(Weight(c[1]))))
+		}
+		if config.signal != nil {
+			color := viridis(config.signal[i], lo, hi)
+			attributes = append(attributes, fmt.Sprintf("fillcolor=%q", color))
+			if config.signal[i] < (lo+hi)/2 {
+				// Dark fills need light text
+				attributes = append(attributes, `fontcolor="white"`)
+			}
+		}
+		fmt.Fprintf(bw, "  %s%s;\n", quoteDOT(g.Label(n)), dotAttributes(attributes))
+	}
+	err := edges(g, func(u, v Node, weight Weight) error {
+		_, err := fmt.Fprintf(bw, "  %s %s %s [w=%s];\n", quoteDOT(g.Label(u)), arrow, quoteDOT(g.Label(v)), formatWeight(weight))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func dotAttributes(attributes []string) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attributes, ", ") + "]"
+}
+
+// quoteDOT returns a DOT identifier as a quoted string.
+func quoteDOT(id string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(id) + `"`
+}
+
+// viridisStops samples the viridis colour map at 0, 1/8, ..., 1.
+var viridisStops = [][3]float64{
+	{68, 1, 84}, {71, 44, 122}, {59, 81, 139}, {44, 113, 142}, {33, 144, 141},
+	{39, 173, 129}, {92, 200, 99}, {170, 220, 50}, {253, 231, 37},
+}
+
+// viridis returns the colour of x on the viridis scale from lo to hi as #rrggbb.
+func viridis(x, lo, hi float64) string {
+	t := 0.5
+	if hi > lo {
+		t = (x - lo) / (hi - lo)
+	}
+	t = math.Max(0, math.Min(1, t)) * float64(len(viridisStops)-1)
+	k := int(t)
+	if k == len(viridisStops)-1 {
+		k--
+	}
+	frac := t - float64(k)
+	var rgb [3]int
+	for c := range rgb {
+		rgb[c] = int(math.Round(viridisStops[k][c] + frac*(viridisStops[k+1][c]-viridisStops[k][c])))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,52 @@
+package graphs
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(0, 1, 0.35)
+	g.AddEdge(1, 2, 2)
+	if err := g.SetLabel(2, `say "hi"`); err != nil {
+		t.Fatal(err)
+	}
+	for i, n := range g.Nodes() {
+		if err := g.SetCoordinates(n, float64(i), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, g, ColorBy([]float64{0, 0.5, 1})); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`graph "G" {`,
+		`"0" [pos="0,1!", fillcolor="#440154", fontcolor="white"];`,
+		`"say \"hi\"" [pos="2,1!", fillcolor="#fde725"];`,
+		`"0" -- "1" [w=0.35];`,
+		`"1" -- "say \"hi\"" [w=2];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in\n%s", want, out)
+		}
+	}
+	// Graphviz only takes integer weights
+	if strings.Contains(out, "weight=") {
+		t.Errorf("the weights are written as the Graphviz weight in\n%s", out)
+	}
+
+	if err := WriteDOT(&buf, g, ColorBy([]float64{1})); err == nil {
+		t.Error("a signal of the wrong size is accepted")
+	}
+	// A single NaN would otherwise spoil the colour scale of every node
+	for _, x := range []float64{math.NaN(), math.Inf(1)} {
+		if err := WriteDOT(&buf, g, ColorBy([]float64{0, x, 1})); err == nil {
+			t.Errorf("a signal with %g is accepted", x)
+		}
+	}
+}
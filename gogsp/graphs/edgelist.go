@@ -0,0 +1,209 @@
+// edgelist.go contains the edge list formats: one edge per line as "source target [weight]", separated by
+// whitespace or commas, and the same as a CSV file with a header. A line with a single node declares an isolated
+// node, lines starting with # or % are comments, and a missing weight is 1.
+// Nodes get their matrix index in order of first appearance in the file.
+
+package graphs
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// record is the node names and weight of one line of an edge list.
+type record struct {
+	line   int
+	names  []string
+	weight Weight
+}
+
+// ReadEdgeList reads a whitespace- or comma-separated edge list.
+func ReadEdgeList(r io.Reader, opts ...ReadOption) (*Graph, error) {
+	var records []record
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == '%' {
+			continue
+		}
+		var fields []string
+		if strings.Contains(text, ",") {
+			for _, field := range strings.Split(text, ",") {
+				fields = append(fields, strings.TrimSpace(field))
+			}
+		} else {
+			fields = strings.Fields(text)
+		}
+		rec, err := newRecord(line, fields)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return buildFromRecords(records, opts)
+}
+
+// newRecord parses the fields "node", "source target" or "source target weight".
+func newRecord(line int, fields []string) (record, error) {
+	rec := record{line: line, weight: 1}
+	switch len(fields) {
+	case 1, 2:
+		rec.names = fields
+	case 3:
+		rec.names = fields[:2]
+		w, err := parseWeight(line, fields[2])
+		if err != nil {
+			return rec, err
+		}
+		rec.weight = w
+	default:
+		return rec, parseErrorf(line, "expected 1 to 3 fields, got %d", len(fields))
+	}
+	for _, name := range rec.names {
+		if name == "" {
+			return rec, parseErrorf(line, "empty node name")
+		}
+	}
+	return rec, nil
+}
+
+func buildFromRecords(records []record, opts []ReadOption) (*Graph, error) {
+	b := newBuilder(newReadConfig(opts))
+	var all []string
+	for _, rec := range records {
+		all = append(all, rec.names...)
+	}
+	names := newNodeNames(b.graph, all)
+	for _, rec := range records {
+		u := names.node(rec.names[0])
+		if len(rec.names) == 1 {
+			continue
+		}
+		if err := b.edge(rec.line, u, names.node(rec.names[1]), rec.weight); err != nil {
+			return nil, err
+		}
+	}
+	return b.graph, nil
+}
+
+// WriteEdgeList writes the graph as a whitespace-separated edge list, each undirected edge once, followed by the
+// isolated nodes. Nodes are written by label, which must not contain whitespace, commas or comment characters.
+// Coordinates and attributes are not written, the format has no place for them; use WriteGraphML to keep them.
+func WriteEdgeList(w io.Writer, g *Graph) error {
+	for _, n := range g.Nodes() {
+		if label := g.Label(n); label == "" || strings.ContainsAny(label, " \t\r\n,#%") {
+			return fmt.Errorf("node label %q cannot be written in an edge list", label)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	err := edges(g, func(u, v Node, weight Weight) error {
+		_, err := fmt.Fprintf(bw, "%s %s %s\n", g.Label(u), g.Label(v), formatWeight(weight))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, n := range isolated(g) {
+		if _, err := fmt.Fprintln(bw, g.Label(n)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// isolated returns the nodes without any edge, in matrix order.
+func isolated(g *Graph) []Node {
+	linked := make(map[Node]bool)
+	for n, edges := range g.AdjacencyList {
+		for _, edge := range edges {
+			linked[n] = true
+			linked[edge.Node] = true
+		}
+	}
+	var nodes []Node
+	for _, n := range g.Nodes() {
+		if !linked[n] {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// csvHeaderNames are the column names that mark the first row of an edge CSV as a header.
+var csvHeaderNames = map[string]bool{
+	"source": true, "target": true, "weight": true, "from": true, "to": true, "node": true, "src": true, "dst": true,
+}
+
+// ReadEdgeCSV reads an edge list in CSV format, with rows "source,target[,weight]" or "node". A first row made of
+// column names such as source, target and weight is skipped.
+func ReadEdgeCSV(r io.Reader, opts ...ReadOption) (*Graph, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	var records []record
+	for first := true; ; first = false {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &ParseError{Line: parseErr.Line, Err: parseErr.Err}
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if first && isHeader(fields) {
+			continue
+		}
+		rec, err := newRecord(line, fields)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return buildFromRecords(records, opts)
+}
+
+func isHeader(fields []string) bool {
+	for _, field := range fields {
+		if !csvHeaderNames[strings.ToLower(strings.TrimSpace(field))] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteEdgeCSV writes the graph as a CSV edge list with a "source,target,weight" header, each undirected edge once,
+// followed by the isolated nodes on rows of their own. Coordinates and attributes are not written.
+func WriteEdgeCSV(w io.Writer, g *Graph) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"source", "target", "weight"}); err != nil {
+		return err
+	}
+	err := edges(g, func(u, v Node, weight Weight) error {
+		return writer.Write([]string{g.Label(u), g.Label(v), formatWeight(weight)})
+	})
+	if err != nil {
+		return err
+	}
+	for _, n := range isolated(g) {
+		if err := writer.Write([]string{g.Label(n)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
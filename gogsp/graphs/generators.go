@@ -0,0 +1,213 @@
+// generators.go contains deterministic constructors for the classic graphs of graph signal processing.
+// Nodes are numbered from 0 in matrix order, every edge has the same weight, and every node is given 2D coordinates
+// so that the graph can be drawn. The options are passed on to NewGraph, e.g. to choose the Laplacian.
+// The combinatorial Laplacian spectra of the ring, path and grid are known in closed form:
+//
+//	Ring(n):       2 w (1 - cos(2πk/n)),                     k = 0..n-1
+//	Path(n):       2 w (1 - cos(πk/n)),                      k = 0..n-1
+//	Grid2D(r, c):  2 w (2 - cos(πi/r) - cos(πj/c)),          i = 0..r-1, j = 0..c-1
+//	Torus(r, c):   2 w (2 - cos(2πi/r) - cos(2πj/c)),        i = 0..r-1, j = 0..c-1
+
+package graphs
+
+import (
+	"fmt"
+	"math"
+)
+
+// newGenerated returns a graph with nodes 0..n-1 added in order.
+func newGenerated(n int, weight Weight, opts []GraphOption) (*Graph, error) {
+	if weight <= 0 {
+		return nil, fmt.Errorf("edge weight must be positive, got %v", weight)
+	}
+	g := NewGraph(opts...)
+	for i := 0; i < n; i++ {
+		g.AddNode(Node(i))
+	}
+	return g, nil
+}
+
+// place sets the coordinates of a node the generator has just added.
+func place(g *Graph, n int, x, y float64) {
+	// The node exists and every node gets two coordinates, so this cannot fail
+	_ = g.SetCoordinates(Node(n), x, y)
+}
+
+// circle places nodes first..first+n-1 evenly on a circle, starting at the angle phase.
+func circle(g *Graph, first, n int, cx, cy, radius, phase float64) {
+	for i := 0; i < n; i++ {
+		angle := phase + 2*math.Pi*float64(i)/float64(n)
+		place(g, first+i, cx+radius*math.Cos(angle), cy+radius*math.Sin(angle))
+	}
+}
+
+// Ring returns the cycle on n >= 3 nodes, drawn on the unit circle.
+func Ring(n int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if n < 3 {
+		return nil, fmt.Errorf("a ring needs at least 3 nodes, got %d", n)
+	}
+	g, err := newGenerated(n, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		g.AddEdge(Node(i), Node((i+1)%n), weight)
+	}
+	circle(g, 0, n, 0, 0, 1, 0)
+	return g, nil
+}
+
+// Path returns the path on n >= 2 nodes, drawn along the x axis.
+func Path(n int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("a path needs at least 2 nodes, got %d", n)
+	}
+	g, err := newGenerated(n, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		if i+1 < n {
+			g.AddEdge(Node(i), Node(i+1), weight)
+		}
+		place(g, i, float64(i), 0)
+	}
+	return g, nil
+}
+
+// Grid2D returns the rows x cols lattice, where node r*cols + c sits at (c, r) and is linked to its four neighbours.
+func Grid2D(rows, cols int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	return lattice(rows, cols, weight, false, opts)
+}
+
+// Torus returns the rows x cols lattice with periodic boundaries, i.e. the grid whose opposite sides are linked.
+// Both sides need at least 3 nodes, otherwise the wrap-around edges would duplicate the others.
+func Torus(rows, cols int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if rows < 3 || cols < 3 {
+		return nil, fmt.Errorf("a torus needs at least 3 x 3 nodes, got %d x %d", rows, cols)
+	}
+	return lattice(rows, cols, weight, true, opts)
+}
+
+func lattice(rows, cols int, weight Weight, periodic bool, opts []GraphOption) (*Graph, error) {
+	if rows < 1 || cols < 1 || rows*cols < 2 {
+		return nil, fmt.Errorf("a grid needs at least 2 nodes, got %d x %d", rows, cols)
+	}
+	g, err := newGenerated(rows*cols, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	node := func(r, c int) Node {
+		return Node(r*cols + c)
+	}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if c+1 < cols {
+				g.AddEdge(node(r, c), node(r, c+1), weight)
+			} else if periodic {
+				g.AddEdge(node(r, c), node(r, 0), weight)
+			}
+			if r+1 < rows {
+				g.AddEdge(node(r, c), node(r+1, c), weight)
+			} else if periodic {
+				g.AddEdge(node(r, c), node(0, c), weight)
+			}
+			place(g, int(node(r, c)), float64(c), float64(r))
+		}
+	}
+	return g, nil
+}
+
+// Star returns the star on n >= 2 nodes: node 0 at the origin is linked to n-1 leaves on the unit circle.
+func Star(n int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("a star needs at least 2 nodes, got %d", n)
+	}
+	g, err := newGenerated(n, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	place(g, 0, 0, 0)
+	for i := 1; i < n; i++ {
+		g.AddEdge(0, Node(i), weight)
+	}
+	circle(g, 1, n-1, 0, 0, 1, 0)
+	return g, nil
+}
+
+// Complete returns the complete graph on n >= 2 nodes, drawn on the unit circle.
+func Complete(n int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("a complete graph needs at least 2 nodes, got %d", n)
+	}
+	g, err := newGenerated(n, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	clique(g, 0, n, weight)
+	circle(g, 0, n, 0, 0, 1, 0)
+	return g, nil
+}
+
+// clique links every pair of nodes first..first+n-1.
+func clique(g *Graph, first, n int, weight Weight) {
+	for i := first; i < first+n; i++ {
+		for j := i + 1; j < first+n; j++ {
+			g.AddEdge(Node(i), Node(j), weight)
+		}
+	}
+}
+
+// Comet returns a star with k branches and a tail: node 0 is linked to the k branch nodes 1..k and to the path
+// k+1..n-1. The branches are drawn on the unit circle and the tail along the positive x axis.
+func Comet(n, k int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if k < 1 || n < k+2 {
+		return nil, fmt.Errorf("a comet with %d branches needs at least %d nodes, got %d", k, k+2, n)
+	}
+	g, err := newGenerated(n, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	place(g, 0, 0, 0)
+	for i := 1; i <= k; i++ {
+		g.AddEdge(0, Node(i), weight)
+		// Leave the direction of the tail free
+		angle := 2 * math.Pi * float64(i) / float64(k+1)
+		place(g, i, math.Cos(angle), math.Sin(angle))
+	}
+	previous := Node(0)
+	for i := k + 1; i < n; i++ {
+		g.AddEdge(previous, Node(i), weight)
+		place(g, i, float64(i-k), 0)
+		previous = Node(i)
+	}
+	return g, nil
+}
+
+// Barbell returns two complete graphs on m >= 2 nodes joined by a path through p >= 0 extra nodes.
+// Nodes 0..m-1 form the left clique, m..m+p-1 the path and m+p..2m+p-1 the right clique; the path runs from node
+// m-1 to node m+p.
+func Barbell(m, p int, weight Weight, opts ...GraphOption) (*Graph, error) {
+	if m < 2 || p < 0 {
+		return nil, fmt.Errorf("a barbell needs cliques of at least 2 nodes and a path of at least 0, got %d and %d", m, p)
+	}
+	g, err := newGenerated(2*m+p, weight, opts)
+	if err != nil {
+		return nil, err
+	}
+	right := m + p
+	clique(g, 0, m, weight)
+	clique(g, right, m, weight)
+	for i := m - 1; i < right; i++ {
+		g.AddEdge(Node(i), Node(i+1), weight)
+	}
+
+	// The cliques are drawn as circles facing each other, with node m-1 and node m+p on the facing sides
+	half := float64(p+1)/2 + 1
+	circle(g, 0, m, -half, 0, 1, 2*math.Pi/float64(m))
+	circle(g, right, m, half, 0, 1, math.Pi)
+	for i := m; i < right; i++ {
+		place(g, i, float64(i-m+2)-half, 0)
+	}
+	return g, nil
+}
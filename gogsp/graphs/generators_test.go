@@ -0,0 +1,127 @@
+package graphs
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// checkSpectrum compares the Laplacian eigenvalues of the graph with the expected ones, in any order.
+func checkSpectrum(t *testing.T, name string, g *Graph, want []float64) {
+	t.Helper()
+	basis, err := g.Basis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Float64s(want)
+	if len(basis.Eigenvalues) != len(want) {
+		t.Fatalf("%s: got %d eigenvalues, want %d", name, len(basis.Eigenvalues), len(want))
+	}
+	for k := range want {
+		if math.Abs(basis.Eigenvalues[k]-want[k]) > 1e-10 {
+			t.Errorf("%s: eigenvalue %d is %.12f, want %.12f", name, k, basis.Eigenvalues[k], want[k])
+		}
+	}
+}
+
+func TestClosedFormSpectra(t *testing.T) {
+	const w = 0.5
+
+	ring, err := Ring(9, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []float64
+	for k := 0; k < 9; k++ {
+		want = append(want, 2*w*(1-math.Cos(2*math.Pi*float64(k)/9)))
+	}
+	checkSpectrum(t, "ring", ring, want)
+
+	path, err := Path(10, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = nil
+	for k := 0; k < 10; k++ {
+		want = append(want, 2*w*(1-math.Cos(math.Pi*float64(k)/10)))
+	}
+	checkSpectrum(t, "path", path, want)
+
+	grid, err := Grid2D(4, 6, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = nil
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 6; j++ {
+			want = append(want, 2*w*(2-math.Cos(math.Pi*float64(i)/4)-math.Cos(math.Pi*float64(j)/6)))
+		}
+	}
+	checkSpectrum(t, "grid", grid, want)
+
+	torus, err := Torus(4, 5, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = nil
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			want = append(want, 2*w*(2-math.Cos(2*math.Pi*float64(i)/4)-math.Cos(2*math.Pi*float64(j)/5)))
+		}
+	}
+	checkSpectrum(t, "torus", torus, want)
+}
+
+// countEdges returns the number of nodes and undirected edges of the graph.
+func countEdges(g *Graph) (nodes, edges int) {
+	for _, list := range g.AdjacencyList {
+		edges += len(list)
+	}
+	return len(g.AdjacencyList), edges / 2
+}
+
+func TestGeneratorSizes(t *testing.T) {
+	tests := []struct {
+		name         string
+		build        func() (*Graph, error)
+		nodes, edges int
+	}{
+		{"torus", func() (*Graph, error) { return Torus(3, 5, 1) }, 15, 30},
+		{"star", func() (*Graph, error) { return Star(7, 1) }, 7, 6},
+		{"complete", func() (*Graph, error) { return Complete(6, 1) }, 6, 15},
+		{"comet", func() (*Graph, error) { return Comet(10, 4, 1) }, 10, 9},
+		{"barbell", func() (*Graph, error) { return Barbell(5, 3, 1) }, 13, 24},
+		{"barbell without path", func() (*Graph, error) { return Barbell(3, 0, 1) }, 6, 7},
+	}
+	for _, test := range tests {
+		g, err := test.build()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		nodes, edges := countEdges(g)
+		if nodes != test.nodes || edges != test.edges {
+			t.Errorf("%s: got %d nodes and %d edges, want %d and %d", test.name, nodes, edges, test.nodes, test.edges)
+		}
+		if !g.IsFullyConnected() {
+			t.Errorf("%s: the graph is not connected", test.name)
+		}
+		if d := g.CoordinateDims(); d != 2 {
+			t.Errorf("%s: nodes have %d coordinates, want 2", test.name, d)
+		}
+	}
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	if _, err := Ring(2, 1); err == nil {
+		t.Error("a ring of 2 nodes is accepted")
+	}
+	if _, err := Torus(2, 5, 1); err == nil {
+		t.Error("a 2 x 5 torus is accepted")
+	}
+	if _, err := Comet(3, 2, 1); err == nil {
+		t.Error("a comet without a tail is accepted")
+	}
+	if _, err := Path(5, 0); err == nil {
+		t.Error("a zero weight is accepted")
+	}
+}
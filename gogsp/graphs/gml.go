@@ -0,0 +1,490 @@
+// gml.go contains GML, the Graph Modelling Language, a nested list of "key value" pairs in which a value is an
+// integer, a real, a quoted string or a bracketed list. Nodes need an integer id, which is kept as the node ID, and
+// may have a label and a position as "graphics [ x .. y .. z .. ]". Edges have a source, a target and a weight, read
+// from "value" when there is no "weight" as some tools write it. Every other scalar is an attribute: integers are
+// IntAttribute, reals FloatAttribute and strings StringAttribute. GML has no booleans, so graphs with BoolAttribute
+// values cannot be written; store them as integers instead.
+
+package graphs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// gmlKind is the type of a GML value.
+type gmlKind int
+
+const (
+	gmlInt gmlKind = iota
+	gmlReal
+	gmlString
+	gmlList
+)
+
+// gmlValue is a GML value with the line it starts on.
+type gmlValue struct {
+	line int
+	kind gmlKind
+	text string
+	list []gmlPair
+}
+
+type gmlPair struct {
+	key   string
+	value gmlValue
+}
+
+// gmlScanner splits a GML file into keys, numbers, strings and brackets.
+type gmlScanner struct {
+	reader *bufio.Reader
+	line   int
+}
+
+// next returns the next token with its line, and "" at the end of the file. Strings keep their quotes.
+func (s *gmlScanner) next() (string, int, error) {
+	for {
+		r, _, err := s.reader.ReadRune()
+		if errors.Is(err, io.EOF) {
+			return "", s.line, nil
+		}
+		if err != nil {
+			return "", s.line, err
+		}
+		switch {
+		case r == '\n':
+			s.line++
+		case r == ' ' || r == '\t' || r == '\r':
+		case r == '#':
+			// Comment up to the end of the line
+			if _, err := s.reader.ReadString('\n'); err != nil && !errors.Is(err, io.EOF) {
+				return "", s.line, err
+			}
+			s.line++
+		case r == '[' || r == ']':
+			return string(r), s.line, nil
+		case r == '"':
+			text, err := s.reader.ReadString('"')
+			if err != nil {
+				return "", s.line, parseErrorf(s.line, "unterminated string")
+			}
+			line := s.line
+			s.line += strings.Count(text, "\n")
+			return `"` + text, line, nil
+		default:
+			var sb strings.Builder
+			sb.WriteRune(r)
+			for {
+				r, _, err := s.reader.ReadRune()
+				if err != nil {
+					break
+				}
+				if r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '[' || r == ']' || r == '"' {
+					_ = s.reader.UnreadRune()
+					break
+				}
+				sb.WriteRune(r)
+			}
+			return sb.String(), s.line, nil
+		}
+	}
+}
+
+// list reads "key value" pairs up to a closing bracket, or up to the end of the file at the top level.
+func (s *gmlScanner) list(top bool) ([]gmlPair, error) {
+	var pairs []gmlPair
+	for {
+		key, line, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case key == "":
+			if !top {
+				return nil, parseErrorf(line, "missing ]")
+			}
+			return pairs, nil
+		case key == "]":
+			if top {
+				return nil, parseErrorf(line, "unexpected ]")
+			}
+			return pairs, nil
+		case !isGMLKey(key):
+			return nil, parseErrorf(line, "expected a key, got %q", key)
+		}
+
+		token, line, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		value := gmlValue{line: line}
+		switch {
+		case token == "" || token == "]":
+			return nil, parseErrorf(line, "key %q has no value", key)
+		case token == "[":
+			value.kind = gmlList
+			if value.list, err = s.list(false); err != nil {
+				return nil, err
+			}
+		case token[0] == '"':
+			value.kind, value.text = gmlString, html.UnescapeString(token[1:len(token)-1])
+		default:
+			if _, err := strconv.ParseInt(token, 10, 64); err == nil {
+				value.kind = gmlInt
+			} else if _, err := strconv.ParseFloat(token, 64); err == nil {
+				value.kind = gmlReal
+			} else {
+				return nil, parseErrorf(line, "invalid value %q for key %q", token, key)
+			}
+			value.text = token
+		}
+		pairs = append(pairs, gmlPair{key: key, value: value})
+	}
+}
+
+func isGMLKey(token string) bool {
+	for i, r := range token {
+		letter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_'
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return token != ""
+}
+
+// number returns a numeric value as a float64.
+func (v gmlValue) number(key string) (float64, error) {
+	if v.kind != gmlInt && v.kind != gmlReal {
+		return 0, parseErrorf(v.line, "%s should be a number", key)
+	}
+	f, _ := strconv.ParseFloat(v.text, 64)
+	return f, nil
+}
+
+// attribute returns a scalar value as an attribute value.
+func (v gmlValue) attribute() Value {
+	switch v.kind {
+	case gmlInt:
+		i, _ := strconv.ParseInt(v.text, 10, 64)
+		return IntValue(i)
+	case gmlReal:
+		f, _ := strconv.ParseFloat(v.text, 64)
+		return FloatValue(f)
+	}
+	return StringValue(v.text)
+}
+
+// ReadGML reads the first graph of a GML file. The graph is directed if the file says "directed 1", unless the
+// Directed option is given.
+func ReadGML(r io.Reader, opts ...ReadOption) (*Graph, error) {
+	scanner := &gmlScanner{reader: bufio.NewReader(r), line: 1}
+	top, err := scanner.list(true)
+	if err != nil {
+		return nil, err
+	}
+	var graph *gmlValue
+	for i := range top {
+		if top[i].key == "graph" && top[i].value.kind == gmlList {
+			graph = &top[i].value
+			break
+		}
+	}
+	if graph == nil {
+		return nil, errors.New("no graph in the GML file")
+	}
+
+	config := newReadConfig(opts)
+	for _, pair := range graph.list {
+		if pair.key == "directed" && pair.value.kind == gmlInt && pair.value.text != "0" {
+			config.graph = append([]GraphOption{Directed(OutDegree)}, config.graph...)
+		}
+	}
+	b := newBuilder(config)
+	for _, pair := range graph.list {
+		if pair.key != "node" {
+			continue
+		}
+		if pair.value.kind != gmlList {
+			return nil, parseErrorf(pair.value.line, "node should be a list")
+		}
+		if err := readGMLNode(b.graph, pair.value); err != nil {
+			return nil, err
+		}
+	}
+	for _, pair := range graph.list {
+		if pair.key != "edge" {
+			continue
+		}
+		if pair.value.kind != gmlList {
+			return nil, parseErrorf(pair.value.line, "edge should be a list")
+		}
+		if err := readGMLEdge(b, pair.value); err != nil {
+			return nil, err
+		}
+	}
+	return b.graph, nil
+}
+
+func readGMLNode(g *Graph, node gmlValue) error {
+	var id *gmlValue
+	for i := range node.list {
+		if node.list[i].key == "id" {
+			id = &node.list[i].value
+		}
+	}
+	if id == nil || id.kind != gmlInt {
+		return parseErrorf(node.line, "node without an integer id")
+	}
+	i, err := strconv.Atoi(id.text)
+	if err != nil {
+		return parseErrorf(id.line, "invalid node id %q", id.text)
+	}
+	n := Node(i)
+	if _, present := g.AdjacencyList[n]; present {
+		return parseErrorf(id.line, "node %v is given twice", n)
+	}
+	g.AddNode(n)
+
+	for _, pair := range node.list {
+		v := pair.value
+		switch pair.key {
+		case "id":
+		case "label":
+			if v.text != id.text {
+				if err := g.SetLabel(n, v.text); err != nil {
+					return parseErrorf(v.line, "%v", err)
+				}
+			}
+		case "graphics":
+			if v.kind != gmlList {
+				return parseErrorf(v.line, "graphics should be a list")
+			}
+			if err := readGMLPosition(g, n, v); err != nil {
+				return err
+			}
+		default:
+			if v.kind == gmlList {
+				continue
+			}
+			if err := g.SetNodeAttribute(n, pair.key, v.attribute()); err != nil {
+				return parseErrorf(v.line, "%v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// readGMLPosition reads the coordinates x, y and z of a graphics list.
+func readGMLPosition(g *Graph, n Node, graphics gmlValue) error {
+	axes := make(map[string]float64)
+	for _, pair := range graphics.list {
+		if pair.key == "x" || pair.key == "y" || pair.key == "z" {
+			f, err := pair.value.number(pair.key)
+			if err != nil {
+				return err
+			}
+			axes[pair.key] = f
+		}
+	}
+	var coordinates []float64
+	for _, axis := range []string{"x", "y", "z"} {
+		f, ok := axes[axis]
+		if !ok {
+			break
+		}
+		coordinates = append(coordinates, f)
+	}
+	if len(coordinates) == 0 {
+		return nil
+	}
+	if err := g.SetCoordinates(n, coordinates...); err != nil {
+		return parseErrorf(graphics.line, "%v", err)
+	}
+	return nil
+}
+
+func readGMLEdge(b *builder, edge gmlValue) error {
+	ends := make(map[string]Node)
+	weight, weighted := Weight(1), false
+	values := make(map[string]Value)
+	for _, pair := range edge.list {
+		v := pair.value
+		switch pair.key {
+		case "source", "target":
+			if v.kind != gmlInt {
+				return parseErrorf(v.line, "%s should be a node id", pair.key)
+			}
+			id, err := strconv.Atoi(v.text)
+			if err != nil {
+				return parseErrorf(v.line, "invalid node id %q", v.text)
+			}
+			if _, present := b.graph.AdjacencyList[Node(id)]; !present {
+				return parseErrorf(v.line, "%s %d is not a node of the graph", pair.key, id)
+			}
+			ends[pair.key] = Node(id)
+		case "weight", "value":
+			if pair.key == "value" && weighted {
+				continue
+			}
+			f, err := v.number(pair.key)
+			if err != nil {
+				return err
+			}
+			weight, weighted = Weight(f), pair.key == "weight"
+		default:
+			if v.kind != gmlList {
+				values[pair.key] = v.attribute()
+			}
+		}
+	}
+	u, ok1 := ends["source"]
+	v, ok2 := ends["target"]
+	if !ok1 || !ok2 {
+		return parseErrorf(edge.line, "edge without a source or target")
+	}
+	if err := b.edge(edge.line, u, v, weight); err != nil {
+		return err
+	}
+	if !b.graph.HasEdge(u, v) {
+		// A dropped self-loop
+		return nil
+	}
+	for key, value := range values {
+		if err := b.graph.SetEdgeAttribute(u, v, key, value); err != nil {
+			return parseErrorf(edge.line, "%v", err)
+		}
+	}
+	return nil
+}
+
+// WriteGML writes the graph with its labels, coordinates, weights and attributes, nodes in matrix order and each
+// undirected edge once. Coordinates are written as graphics x, y and z, so at most 3 are supported, and reals must
+// be finite.
+func WriteGML(w io.Writer, g *Graph) error {
+	dims := g.CoordinateDims()
+	if dims > 3 {
+		return fmt.Errorf("GML coordinates have at most 3 dimensions, the graph has %d", dims)
+	}
+	for _, key := range g.NodeAttributeKeys() {
+		if key == "id" || key == "label" || key == "graphics" {
+			return fmt.Errorf("node attribute %q uses a reserved GML key", key)
+		}
+	}
+	for _, key := range g.EdgeAttributeKeys() {
+		if key == "source" || key == "target" || key == "weight" || key == "value" {
+			return fmt.Errorf("edge attribute %q uses a reserved GML key", key)
+		}
+	}
+	for _, key := range append(g.NodeAttributeKeys(), g.EdgeAttributeKeys()...) {
+		if !isGMLKey(key) {
+			return fmt.Errorf("attribute %q is not a valid GML key", key)
+		}
+	}
+	for _, key := range g.NodeAttributeKeys() {
+		if kind, _ := g.NodeAttributeKind(key); kind == BoolAttribute {
+			return fmt.Errorf("node attribute %q is boolean, which GML cannot hold", key)
+		}
+	}
+	for _, key := range g.EdgeAttributeKeys() {
+		if kind, _ := g.EdgeAttributeKind(key); kind == BoolAttribute {
+			return fmt.Errorf("edge attribute %q is boolean, which GML cannot hold", key)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	var err error
+	pair := func(indent, key, value string) {
+		if err == nil {
+			_, err = fmt.Fprintf(bw, "%s%s %s\n", indent, key, value)
+		}
+	}
+	number := func(indent, key string, f float64) {
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			if err == nil {
+				err = fmt.Errorf("%s %v cannot be written in GML", key, f)
+			}
+			return
+		}
+		pair(indent, key, formatGMLReal(f))
+	}
+	attribute := func(indent, key string, v Value) {
+		switch v.Kind() {
+		case FloatAttribute:
+			f, _ := v.Float()
+			number(indent, key, f)
+		case StringAttribute:
+			pair(indent, key, quoteGML(v.String()))
+		default:
+			pair(indent, key, v.String())
+		}
+	}
+
+	bw.WriteString("graph [\n")
+	directed := "0"
+	if g.IsDirected() {
+		directed = "1"
+	}
+	pair("  ", "directed", directed)
+	nodeKeys := g.NodeAttributeKeys()
+	for _, n := range g.Nodes() {
+		bw.WriteString("  node [\n")
+		pair("    ", "id", strconv.Itoa(int(n)))
+		if label := g.Label(n); label != strconv.Itoa(int(n)) {
+			pair("    ", "label", quoteGML(label))
+		}
+		if c, ok := g.Coordinates(n); ok {
+			bw.WriteString("    graphics [\n")
+			for k, x := range c {
+				number("      ", []string{"x", "y", "z"}[k], x)
+			}
+			bw.WriteString("    ]\n")
+		}
+		attributes := g.NodeAttributes(n)
+		for _, key := range nodeKeys {
+			if v, ok := attributes[key]; ok {
+				attribute("    ", key, v)
+			}
+		}
+		bw.WriteString("  ]\n")
+	}
+	edgeKeys := g.EdgeAttributeKeys()
+	_ = edges(g, func(u, v Node, weight Weight) error {
+		bw.WriteString("  edge [\n")
+		pair("    ", "source", strconv.Itoa(int(u)))
+		pair("    ", "target", strconv.Itoa(int(v)))
+		number("    ", "weight", float64(weight))
+		attributes := g.EdgeAttributes(u, v)
+		for _, key := range edgeKeys {
+			if value, ok := attributes[key]; ok {
+				attribute("    ", key, value)
+			}
+		}
+		bw.WriteString("  ]\n")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// formatGMLReal prints a real so that it is not read back as an integer.
+func formatGMLReal(f float64) string {
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
+
+// quoteGML quotes a string, escaping the characters GML strings cannot hold as HTML entities.
+func quoteGML(text string) string {
+	return `"` + strings.NewReplacer("&", "&amp;", `"`, "&quot;").Replace(text) + `"`
+}
@@ -0,0 +1,23 @@
+package graphs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGMLRoundTrip(t *testing.T) {
+	checkRoundTrip(t, sampleGraph(t), WriteGML, ReadGML)
+	checkRoundTrip(t, sampleGraph(t, Directed(OutDegree)), WriteGML, ReadGML)
+}
+
+func TestGMLRejectsBooleans(t *testing.T) {
+	g := sampleGraph(t)
+	if err := g.SetEdgeAttribute(3, 7, "toll", BoolValue(true)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteGML(&buf, g); err == nil || !strings.Contains(err.Error(), `"toll" is boolean`) {
+		t.Errorf("writing a boolean attribute gives %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+// graphml.go contains GraphML, the XML format of most graph tools. Node and edge attributes are declared as typed
+// keys, see AttributeKind. A few key names have a meaning of their own: "weight" on edges is the edge weight, 1 when
+// missing, "label" on nodes is the node label, and "x", "y" and "z" on nodes are the coordinates.
+// Only the first graph of a file is read; nested graphs, hyperedges and ports are not supported.
+
+package graphs
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"example/gogsp/core"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+// graphMLWeight, graphMLLabel and graphMLAxes are the key names read as weights, labels and coordinates.
+const (
+	graphMLWeight = "weight"
+	graphMLLabel  = "label"
+)
+
+var graphMLAxes = []string{"x", "y", "z"}
+
+type graphMLKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Type    string  `xml:"attr.type,attr"`
+	Default *string `xml:"default"`
+}
+
+// appliesTo reports whether the key is declared for the domain; GraphML keys without a domain are for all elements.
+func (k graphMLKey) appliesTo(domain string) bool {
+	return k.For == domain || k.For == "all" || k.For == ""
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLKinds maps the GraphML attribute types to attribute kinds.
+var graphMLKinds = map[string]AttributeKind{
+	"boolean": BoolAttribute,
+	"int":     IntAttribute,
+	"long":    IntAttribute,
+	"float":   FloatAttribute,
+	"double":  FloatAttribute,
+	"string":  StringAttribute,
+}
+
+// graphMLTypes maps attribute kinds to the GraphML attribute types written.
+var graphMLTypes = map[AttributeKind]string{
+	BoolAttribute:   "boolean",
+	IntAttribute:    "long",
+	FloatAttribute:  "double",
+	StringAttribute: "string",
+}
+
+// ReadGraphML reads the first graph of a GraphML file. The graph is directed if its edgedefault is, unless the
+// Directed option is given, and node IDs are kept when they are all integers.
+func ReadGraphML(r io.Reader, opts ...ReadOption) (*Graph, error) {
+	decoder := xml.NewDecoder(r)
+	keys := make(map[string]graphMLKey)
+	var nodes []graphMLNode
+	var nodeLines []int
+	var edges []graphMLEdge
+	var edgeLines []int
+	directed, inGraph, found := false, false, false
+
+	for {
+		line, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, graphMLError(decoder, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "key":
+				var key graphMLKey
+				if err := decoder.DecodeElement(&key, &t); err != nil {
+					return nil, graphMLError(decoder, err)
+				}
+				keys[key.ID] = key
+			case "graph":
+				if found {
+					// Only the first graph is read
+					if err := decoder.Skip(); err != nil {
+						return nil, graphMLError(decoder, err)
+					}
+					continue
+				}
+				found, inGraph = true, true
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "edgedefault" {
+						directed = attr.Value == "directed"
+					}
+				}
+			case "node":
+				if !inGraph {
+					return nil, parseErrorf(line, "node outside of a graph")
+				}
+				var node graphMLNode
+				if err := decoder.DecodeElement(&node, &t); err != nil {
+					return nil, graphMLError(decoder, err)
+				}
+				if node.ID == "" {
+					return nil, parseErrorf(line, "node without an id")
+				}
+				nodes, nodeLines = append(nodes, node), append(nodeLines, line)
+			case "edge":
+				if !inGraph {
+					return nil, parseErrorf(line, "edge outside of a graph")
+				}
+				var edge graphMLEdge
+				if err := decoder.DecodeElement(&edge, &t); err != nil {
+					return nil, graphMLError(decoder, err)
+				}
+				edges, edgeLines = append(edges, edge), append(edgeLines, line)
+			case "hyperedge", "port":
+				return nil, parseErrorf(line, "GraphML %ss are not supported", t.Name.Local)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "graph" {
+				inGraph = false
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("no graph in the GraphML file")
+	}
+
+	config := newReadConfig(opts)
+	if directed {
+		config.graph = append([]GraphOption{Directed(OutDegree)}, config.graph...)
+	}
+	b := newBuilder(config)
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = node.ID
+	}
+	for _, edge := range edges {
+		names = append(names, edge.Source, edge.Target)
+	}
+	resolver := newNodeNames(b.graph, names)
+
+	attributes := graphMLAttributes{keys: keys}
+	for i, node := range nodes {
+		n := resolver.node(node.ID)
+		if err := attributes.node(b.graph, n, node.Data, nodeLines[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i, edge := range edges {
+		line := edgeLines[i]
+		if edge.Source == "" || edge.Target == "" {
+			return nil, parseErrorf(line, "edge without a source or target")
+		}
+		u, v := resolver.node(edge.Source), resolver.node(edge.Target)
+		weight, values, err := attributes.edge(edge.Data, line)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.edge(line, u, v, weight); err != nil {
+			return nil, err
+		}
+		if !b.graph.HasEdge(u, v) {
+			// A dropped self-loop
+			continue
+		}
+		for key, value := range values {
+			if err := b.graph.SetEdgeAttribute(u, v, key, value); err != nil {
+				return nil, parseErrorf(line, "%v", err)
+			}
+		}
+	}
+	return b.graph, nil
+}
+
+// graphMLError adds the line the decoder stopped on to an XML error.
+func graphMLError(decoder *xml.Decoder, err error) error {
+	var syntax *xml.SyntaxError
+	if errors.As(err, &syntax) {
+		return &ParseError{Line: syntax.Line, Err: errors.New(syntax.Msg)}
+	}
+	line, _ := decoder.InputPos()
+	return &ParseError{Line: line, Err: err}
+}
+
+// graphMLAttributes turns the data elements of nodes and edges into labels, coordinates, weights and attributes.
+type graphMLAttributes struct {
+	keys map[string]graphMLKey
+}
+
+// values returns the data of an element by key name, starting from the defaults of the keys for the domain.
+// Every key used must be declared for the domain, "node" or "edge", or for all elements.
+func (a graphMLAttributes) values(domain string, data []graphMLData, line int) (map[string]Value, error) {
+	values := make(map[string]Value)
+	for _, key := range a.keys {
+		if key.Default != nil && key.appliesTo(domain) {
+			v, err := a.parse(key, *key.Default, line)
+			if err != nil {
+				return nil, err
+			}
+			values[key.Name] = v
+		}
+	}
+	for _, d := range data {
+		key, ok := a.keys[d.Key]
+		if !ok {
+			return nil, parseErrorf(line, "undeclared key %q", d.Key)
+		}
+		if !key.appliesTo(domain) {
+			return nil, parseErrorf(line, "key %q is declared for %s elements, not %s elements", d.Key, key.For, domain)
+		}
+		v, err := a.parse(key, d.Value, line)
+		if err != nil {
+			return nil, err
+		}
+		values[key.Name] = v
+	}
+	return values, nil
+}
+
+func (a graphMLAttributes) parse(key graphMLKey, text string, line int) (Value, error) {
+	kind, ok := graphMLKinds[key.Type]
+	if key.Type == "" {
+		kind, ok = StringAttribute, true
+	}
+	if !ok {
+		return Value{}, parseErrorf(line, "key %q has the unsupported type %q", key.ID, key.Type)
+	}
+	if kind != StringAttribute {
+		text = strings.TrimSpace(text)
+	}
+	v, err := core.ParseValue(kind, text)
+	if err != nil {
+		return Value{}, parseErrorf(line, "invalid %s value %q for key %q", key.Type, text, key.Name)
+	}
+	return v, nil
+}
+
+// node stores the label, coordinates and attributes of a node.
+func (a graphMLAttributes) node(g *Graph, n Node, data []graphMLData, line int) error {
+	values, err := a.values("node", data, line)
+	if err != nil {
+		return err
+	}
+	if v, ok := values[graphMLLabel]; ok {
+		if err := g.SetLabel(n, v.String()); err != nil {
+			return parseErrorf(line, "%v", err)
+		}
+		delete(values, graphMLLabel)
+	}
+	var coordinates []float64
+	for _, axis := range graphMLAxes {
+		v, ok := values[axis]
+		if !ok {
+			break
+		}
+		f, ok := v.Float()
+		if !ok {
+			return parseErrorf(line, "coordinate %q of node %v is not a number", axis, g.Label(n))
+		}
+		coordinates = append(coordinates, f)
+		delete(values, axis)
+	}
+	if len(coordinates) > 0 {
+		if err := g.SetCoordinates(n, coordinates...); err != nil {
+			return parseErrorf(line, "%v", err)
+		}
+	}
+	for key, v := range values {
+		if err := g.SetNodeAttribute(n, key, v); err != nil {
+			return parseErrorf(line, "%v", err)
+		}
+	}
+	return nil
+}
+
+// edge returns the weight and the other attributes of an edge.
+func (a graphMLAttributes) edge(data []graphMLData, line int) (Weight, map[string]Value, error) {
+	values, err := a.values("edge", data, line)
+	if err != nil {
+		return 0, nil, err
+	}
+	weight := Weight(1)
+	if v, ok := values[graphMLWeight]; ok {
+		f, ok := v.Float()
+		if !ok {
+			return 0, nil, parseErrorf(line, "edge weight %q is not a number", v.String())
+		}
+		weight = Weight(f)
+		delete(values, graphMLWeight)
+	}
+	return weight, values, nil
+}
+
+// WriteGraphML writes the graph with its labels, coordinates, weights and attributes, nodes in matrix order and each
+// undirected edge once. Coordinates are written as the keys x, y and z, so at most 3 are supported, and no
+// attribute may use one of the reserved key names.
+func WriteGraphML(w io.Writer, g *Graph) error {
+	nodes := g.Nodes()
+	dims := g.CoordinateDims()
+	if dims > len(graphMLAxes) {
+		return fmt.Errorf("GraphML coordinates have at most %d dimensions, the graph has %d", len(graphMLAxes), dims)
+	}
+	nodeKeys, edgeKeys := g.NodeAttributeKeys(), g.EdgeAttributeKeys()
+	for _, key := range nodeKeys {
+		if key == graphMLLabel || key == "x" || key == "y" || key == "z" {
+			return fmt.Errorf("node attribute %q uses a reserved GraphML key name", key)
+		}
+	}
+	for _, key := range edgeKeys {
+		if key == graphMLWeight {
+			return fmt.Errorf("edge attribute %q uses a reserved GraphML key name", key)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, xml.Header+`<graphml xmlns="`+graphMLNamespace+`">`)
+	ids := make(map[string]string)
+	declare := func(domain, name, kind string) {
+		id := fmt.Sprintf("d%d", len(ids))
+		ids[domain+"/"+name] = id
+		fmt.Fprintf(bw, "  <key id=%q for=%q attr.name=%s attr.type=%q/>\n", id, domain, quoteXML(name), kind)
+	}
+	labeled := false
+	for _, n := range nodes {
+		if g.Label(n) != strconv.Itoa(int(n)) {
+			labeled = true
+			break
+		}
+	}
+	if labeled {
+		declare("node", graphMLLabel, "string")
+	}
+	for _, axis := range graphMLAxes[:dims] {
+		declare("node", axis, "double")
+	}
+	for _, key := range nodeKeys {
+		kind, _ := g.NodeAttributeKind(key)
+		declare("node", key, graphMLTypes[kind])
+	}
+	declare("edge", graphMLWeight, "double")
+	for _, key := range edgeKeys {
+		kind, _ := g.EdgeAttributeKind(key)
+		declare("edge", key, graphMLTypes[kind])
+	}
+
+	edgeDefault := "undirected"
+	if g.IsDirected() {
+		edgeDefault = "directed"
+	}
+	fmt.Fprintf(bw, "  <graph id=\"G\" edgedefault=%q>\n", edgeDefault)
+	data := func(domain, name, value string) {
+		fmt.Fprintf(bw, "      <data key=%q>%s</data>\n", ids[domain+"/"+name], escapeXML(value))
+	}
+	for _, n := range nodes {
+		fmt.Fprintf(bw, "    <node id=\"%d\">\n", int(n))
+		if labeled {
+			data("node", graphMLLabel, g.Label(n))
+		}
+		if c, ok := g.Coordinates(n); ok {
+			for k, x := range c {
+				data("node", graphMLAxes[k], formatWeight(Weight(x)))
+			}
+		}
+		attributes := g.NodeAttributes(n)
+		for _, key := range nodeKeys {
+			if v, ok := attributes[key]; ok {
+				data("node", key, v.String())
+			}
+		}
+		fmt.Fprintln(bw, "    </node>")
+	}
+	err := edges(g, func(u, v Node, weight Weight) error {
+		fmt.Fprintf(bw, "    <edge source=\"%d\" target=\"%d\">\n", int(u), int(v))
+		data("edge", graphMLWeight, formatWeight(weight))
+		attributes := g.EdgeAttributes(u, v)
+		for _, key := range edgeKeys {
+			if value, ok := attributes[key]; ok {
+				data("edge", key, value.String())
+			}
+		}
+		_, err := fmt.Fprintln(bw, "    </edge>")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
+
+// escapeXML escapes text for XML character data.
+func escapeXML(text string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
+
+// quoteXML returns text as a quoted XML attribute value.
+func quoteXML(text string) string {
+	return `"` + escapeXML(text) + `"`
+}
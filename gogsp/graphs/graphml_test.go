@@ -0,0 +1,49 @@
+package graphs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g := sampleGraph(t)
+	if err := g.SetNodeAttribute(7, "capital", BoolValue(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetEdgeAttribute(1, 10, "toll", BoolValue(false)); err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, g, WriteGraphML, ReadGraphML)
+	checkRoundTrip(t, sampleGraph(t, Directed(OutDegree)), WriteGraphML, ReadGraphML)
+}
+
+func TestGraphMLKeyDomain(t *testing.T) {
+	const file = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="edge" attr.name="weight" attr.type="double"/>
+  <key id="d1" for="all" attr.name="color" attr.type="string"/>
+  <key id="d2" attr.name="size" attr.type="int"/>
+  <graph edgedefault="undirected">
+    <node id="a"><data key="d1">red</data><data key="d2">3</data></node>
+    <node id="b"><data key="%s">2</data></node>
+    <edge source="a" target="b"><data key="d0">2.5</data><data key="d1">blue</data></edge>
+  </graph>
+</graphml>`
+	g, err := ReadGraphML(strings.NewReader(strings.Replace(file, "%s", "d2", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := g.NodeByLabel("a")
+	b, _ := g.NodeByLabel("b")
+	if v, _ := g.NodeAttribute(a, "color"); v.String() != "red" {
+		t.Errorf("node a has color %v, want red", v)
+	}
+	if v, _ := g.EdgeAttribute(a, b, "color"); v.String() != "blue" {
+		t.Errorf("the edge has color %v, want blue", v)
+	}
+
+	_, err = ReadGraphML(strings.NewReader(strings.Replace(file, "%s", "d0", 1)))
+	if err == nil || !strings.Contains(err.Error(), `key "d0" is declared for edge elements, not node elements`) {
+		t.Errorf("an edge key on a node gives %v", err)
+	}
+}
@@ -7,121 +7,87 @@
 package graphs
 
 import (
-	"fmt"
+	"example/gogsp/core"
 	"math/rand"
-	"strconv"
 )
 
-type Node int
+// The graph types are defined in core so that signals and filters can use them without importing graphs.
 
-type Weight float64
+type Node = core.Node
 
-type Edge struct {
-	Node   Node
-	Weight Weight
-}
+type Weight = core.Weight
 
-type Graph struct {
-	AdjacencyList   map[Node][]Edge
-	WeightedGraph   [][]Weight
-	LaplacianMatrix [][]Weight
-	AdjacencyMatrix [][]Weight
-}
+type Edge = core.Edge
 
-func NewGraph() *Graph {
-	return &Graph{
-		AdjacencyList:   make(map[Node][]Edge),
-		WeightedGraph:   nil,
-		LaplacianMatrix: nil,
-		AdjacencyMatrix: nil,
-	}
-}
+type Graph = core.Graph
 
-func (g *Graph) AddNode(n Node) {
-	if _, present := g.AdjacencyList[n]; !present {
-		g.AdjacencyList[n] = []Edge{}
-	}
-}
+type DegreeKind = core.DegreeKind
 
-func (g *Graph) AddEdge(n1, n2 Node, weight Weight) {
-	g.AddNode(n1)
-	g.AddNode(n2)
-	g.AdjacencyList[n1] = append(g.AdjacencyList[n1], Edge{n2, weight})
-}
+const (
+	OutDegree = core.OutDegree
+	InDegree  = core.InDegree
+)
 
-func RandomWeightedGraph(size int) *Graph {
-	g := NewGraph()
+type LaplacianKind = core.LaplacianKind
 
-	type Edge struct {
-		Node1  Node
-		Node2  Node
-		Weight Weight
-	}
+const (
+	Combinatorial = core.Combinatorial
+	Normalized    = core.Normalized
+	RandomWalk    = core.RandomWalk
+	Signless      = core.Signless
+)
 
-	// Generate all possible edges with random weights
-	edges := make([]Edge, 0, size*(size-1)/2)
-	for i := 0; i < size; i++ {
-		for j := i + 1; j < size; j++ {
-			weight := Weight(rand.Float64())
-			edges = append(edges, Edge{
-				Node1:  Node(i),
-				Node2:  Node(j),
-				Weight: weight,
-			})
-		}
-	}
+type GraphOption = core.GraphOption
 
-	// Shuffle the edges randomly
-	rand.Shuffle(len(edges), func(i, j int) {
-		edges[i], edges[j] = edges[j], edges[i]
-	})
-
-	uf := NewUnionFind(size)
-	for _, edge := range edges {
-		node1, node2 := edge.Node1, edge.Node2
-		if uf.Find(node1) != uf.Find(node2) {
-			uf.Union(node1, node2)
-			g.AddEdge(node1, node2, edge.Weight)
-			g.AddEdge(node2, node1, edge.Weight)
-		}
-	}
+type AttributeKind = core.AttributeKind
 
-	return g
+const (
+	FloatAttribute  = core.FloatAttribute
+	IntAttribute    = core.IntAttribute
+	StringAttribute = core.StringAttribute
+	BoolAttribute   = core.BoolAttribute
+)
+
+type Value = core.Value
+
+func FloatValue(f float64) Value {
+	return core.FloatValue(f)
 }
 
-func (g *Graph) PrintGraph() {
-	for node, edges := range g.AdjacencyList {
-		fmt.Printf("\nNode %v:", node)
-		for _, edge := range edges {
-			weight := strconv.FormatFloat(float64(edge.Weight), 'f', 2, 64)
-			fmt.Printf("\n%v   Weight: %v", edge.Node, weight)
-		}
-		fmt.Printf("\n")
-	}
+func IntValue(i int64) Value {
+	return core.IntValue(i)
 }
 
-// PrintAdjacencyMatrix prints the adjacency matrix.
-func (g *Graph) PrintAdjacencyMatrix() {
-	fmt.Println("Adjacency Matrix:")
-	for i, row := range g.AdjacencyMatrix {
-		fmt.Printf("Node %v :", Node(i))
-		for _, weight := range row {
-			// Format the weights to two decimal places
-			fmt.Printf(" %.2f", weight)
-		}
-		fmt.Println()
-	}
+func StringValue(s string) Value {
+	return core.StringValue(s)
+}
+
+func BoolValue(b bool) Value {
+	return core.BoolValue(b)
+}
+
+// NewGraph returns an empty graph. Graphs are undirected unless the Directed option is given.
+func NewGraph(opts ...GraphOption) *Graph {
+	return core.NewGraph(opts...)
+}
+
+// Directed makes the graph directed with a Laplacian built from the given degree.
+func Directed(degree DegreeKind) GraphOption {
+	return core.Directed(degree)
 }
 
-// PrintLaplacianMatrix prints the Laplacian matrix.
-func (g *Graph) PrintLaplacianMatrix() {
-	fmt.Println("Laplacian Matrix:")
-	for i, row := range g.LaplacianMatrix {
-		fmt.Printf("Node %v :", Node(i))
-		for _, weight := range row {
-			// Format the weights to two decimal places
-			fmt.Printf(" %.2f", weight)
-		}
-		fmt.Println()
+// WithLaplacian sets the kind of Laplacian built from the graph.
+func WithLaplacian(kind LaplacianKind) GraphOption {
+	return core.WithLaplacian(kind)
+}
+
+// RandomWeightedGraph returns a random spanning tree on size nodes with weights uniform in [0, 1), drawn from the
+// global math/rand source. Use RandomSpanningTree with a seeded *rand.Rand for reproducible graphs.
+func RandomWeightedGraph(size int) *Graph {
+	g, err := RandomSpanningTree(size, rand.New(rand.NewSource(rand.Int63())), WithWeights(UniformWeight(0, 1)))
+	if err != nil {
+		// Only a size below 1 can fail, which used to give an empty graph
+		return NewGraph()
 	}
+	return g
 }
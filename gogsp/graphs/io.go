@@ -0,0 +1,224 @@
+// io.go contains what the graph readers and writers share: the options of the readers, their errors, and the
+// policy applied to duplicate edges and self-loops.
+// Readers keep the node IDs of the file when they are integers. Otherwise every node becomes a labeled node, see
+// Graph.AddLabeledNode, so the names in the file are kept as labels and the writers print them back.
+// Only GraphML and GML hold coordinates and attributes. The edge list, edge CSV, Matrix Market and dense adjacency
+// formats store the edges, their weights and the node names, and their writers leave coordinates and attributes out.
+
+package graphs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError is an error in a graph file, with the line it was found on.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseErrorf returns a ParseError for the line.
+func parseErrorf(line int, format string, args ...interface{}) error {
+	return &ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+// DuplicatePolicy decides what a reader does with an edge given more than once.
+// In an undirected graph the edges (u, v) and (v, u) are the same, so a file listing both directions gives every
+// edge twice and the policy applies to the second entry. Formats that store a symmetric matrix, like the
+// symmetric Matrix Market files and the dense adjacency matrix, only read one triangle of it.
+type DuplicatePolicy int
+
+const (
+	// KeepLast overwrites the weight with the last occurrence, like AddEdge
+	KeepLast DuplicatePolicy = iota
+	// KeepFirst ignores every occurrence after the first
+	KeepFirst
+	// SumWeights adds up the weights of all occurrences, e.g. to build a graph from repeated interactions
+	SumWeights
+	// RejectDuplicates fails on the second occurrence
+	RejectDuplicates
+)
+
+// SelfLoopPolicy decides what a reader does with an edge from a node to itself.
+type SelfLoopPolicy int
+
+const (
+	// KeepSelfLoops stores self-loops in the graph; they are left out of the Laplacian
+	KeepSelfLoops SelfLoopPolicy = iota
+	// DropSelfLoops ignores them
+	DropSelfLoops
+	// RejectSelfLoops fails on the first one
+	RejectSelfLoops
+)
+
+// readConfig holds the settings of the readers.
+type readConfig struct {
+	duplicates DuplicatePolicy
+	selfLoops  SelfLoopPolicy
+	graph      []GraphOption
+}
+
+// ReadOption configures the graph readers.
+type ReadOption func(*readConfig)
+
+// OnDuplicate sets the policy for edges given more than once, KeepLast by default.
+func OnDuplicate(policy DuplicatePolicy) ReadOption {
+	return func(c *readConfig) {
+		c.duplicates = policy
+	}
+}
+
+// OnSelfLoop sets the policy for self-loops, KeepSelfLoops by default.
+func OnSelfLoop(policy SelfLoopPolicy) ReadOption {
+	return func(c *readConfig) {
+		c.selfLoops = policy
+	}
+}
+
+// WithReadGraphOptions passes options, such as Directed, to NewGraph.
+func WithReadGraphOptions(opts ...GraphOption) ReadOption {
+	return func(c *readConfig) {
+		c.graph = append(c.graph, opts...)
+	}
+}
+
+func newReadConfig(opts []ReadOption) *readConfig {
+	c := &readConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// entry is the first occurrence of an edge in a file.
+type entry struct {
+	line   int
+	weight Weight
+}
+
+// builder adds the edges read from a file to a graph, applying the policies.
+type builder struct {
+	config *readConfig
+	graph  *Graph
+	seen   map[[2]Node]entry
+}
+
+func newBuilder(config *readConfig) *builder {
+	return &builder{config: config, graph: NewGraph(config.graph...), seen: make(map[[2]Node]entry)}
+}
+
+// edge adds the edge u -> v found on the given line.
+func (b *builder) edge(line int, u, v Node, w Weight) error {
+	if u == v {
+		switch b.config.selfLoops {
+		case DropSelfLoops:
+			b.graph.AddNode(u)
+			return nil
+		case RejectSelfLoops:
+			return parseErrorf(line, "self-loop on node %v", b.graph.Label(u))
+		}
+	}
+
+	key := [2]Node{u, v}
+	if !b.graph.IsDirected() && v < u {
+		key = [2]Node{v, u}
+	}
+	first, duplicate := b.seen[key]
+	if !duplicate {
+		b.seen[key] = entry{line: line, weight: w}
+		b.graph.AddEdge(u, v, w)
+		return nil
+	}
+	switch b.config.duplicates {
+	case KeepFirst:
+		return nil
+	case SumWeights:
+		first.weight += w
+		b.seen[key] = first
+		b.graph.AddEdge(u, v, first.weight)
+	case RejectDuplicates:
+		return parseErrorf(line, "duplicate edge %v - %v, first given on line %d", b.graph.Label(u), b.graph.Label(v), first.line)
+	default:
+		first.weight = w
+		b.seen[key] = first
+		b.graph.AddEdge(u, v, w)
+	}
+	return nil
+}
+
+// nodeNames resolves the node names of a file: as integer IDs when all of them are integers in canonical form, and
+// as labels of nodes numbered in order of appearance otherwise, so that "007" and "7" stay two nodes.
+type nodeNames struct {
+	graph    *Graph
+	numeric  bool
+	resolved map[string]Node
+}
+
+func newNodeNames(graph *Graph, names []string) *nodeNames {
+	numeric := true
+	for _, name := range names {
+		if id, err := strconv.Atoi(name); err != nil || strconv.Itoa(id) != name {
+			numeric = false
+			break
+		}
+	}
+	return &nodeNames{graph: graph, numeric: numeric, resolved: make(map[string]Node)}
+}
+
+// node returns the node of the name, adding it to the graph on first use.
+func (n *nodeNames) node(name string) Node {
+	if node, ok := n.resolved[name]; ok {
+		return node
+	}
+	var node Node
+	if n.numeric {
+		id, _ := strconv.Atoi(name)
+		node = Node(id)
+		n.graph.AddNode(node)
+	} else {
+		node = n.graph.AddLabeledNode(name)
+	}
+	n.resolved[name] = node
+	return node
+}
+
+// parseWeight reads an edge weight.
+func parseWeight(line int, text string) (Weight, error) {
+	w, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return 0, parseErrorf(line, "invalid weight %q", text)
+	}
+	return Weight(w), nil
+}
+
+// formatWeight prints a weight so that parseWeight reads it back exactly.
+func formatWeight(w Weight) string {
+	return strconv.FormatFloat(float64(w), 'g', -1, 64)
+}
+
+// edges calls fn for every edge of the graph in matrix order, once per undirected edge.
+func edges(g *Graph, fn func(u, v Node, w Weight) error) error {
+	for i, u := range g.Nodes() {
+		for _, edge := range g.AdjacencyList[u] {
+			if !g.IsDirected() {
+				if j, _ := g.NodeIndex(edge.Node); j < i {
+					continue
+				}
+			}
+			if err := fn(u, edge.Node, edge.Weight); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
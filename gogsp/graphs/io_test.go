@@ -0,0 +1,238 @@
+package graphs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// sampleGraph returns a weighted graph with labels, coordinates and attributes of every kind but booleans, which
+// not every format can hold. Node 7 has no label of its own.
+func sampleGraph(t *testing.T, opts ...GraphOption) *Graph {
+	t.Helper()
+	g := NewGraph(opts...)
+	g.AddEdge(3, 7, 0.5)
+	g.AddEdge(7, 1, 2)
+	g.AddEdge(1, 3, 1.25)
+	g.AddEdge(1, 10, 3)
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(g.SetLabel(3, "alpha"))
+	check(g.SetLabel(1, `a "quoted" & <tagged> label`))
+	check(g.SetLabel(10, "ten"))
+	for i, n := range g.Nodes() {
+		check(g.SetCoordinates(n, float64(i), -0.5*float64(i)))
+	}
+	check(g.SetNodeAttribute(3, "population", IntValue(42)))
+	check(g.SetNodeAttribute(7, "population", IntValue(-1)))
+	check(g.SetNodeAttribute(1, "density", FloatValue(0.125)))
+	check(g.SetNodeAttribute(10, "city", StringValue("Zürich & Bern")))
+	check(g.SetEdgeAttribute(3, 7, "kind", StringValue("road")))
+	check(g.SetEdgeAttribute(1, 10, "lanes", IntValue(2)))
+	return g
+}
+
+// checkSameGraph checks that two graphs have the same nodes in the same order, the same Laplacian, labels,
+// coordinates and attributes.
+func checkSameGraph(t *testing.T, want, got *Graph) {
+	t.Helper()
+	wantNodes, gotNodes := want.Nodes(), got.Nodes()
+	if len(wantNodes) != len(gotNodes) {
+		t.Fatalf("got %d nodes, want %d", len(gotNodes), len(wantNodes))
+	}
+	if want.IsDirected() != got.IsDirected() {
+		t.Errorf("got a directed graph %t, want %t", got.IsDirected(), want.IsDirected())
+	}
+	for i, n := range wantNodes {
+		if want.Label(n) != got.Label(gotNodes[i]) {
+			t.Errorf("node %d is %q, want %q", i, got.Label(gotNodes[i]), want.Label(n))
+		}
+		wc, _ := want.Coordinates(n)
+		gc, _ := got.Coordinates(gotNodes[i])
+		if len(wc) != len(gc) {
+			t.Errorf("node %q has coordinates %v, want %v", want.Label(n), gc, wc)
+		} else {
+			for k := range wc {
+				if wc[k] != gc[k] {
+					t.Errorf("node %q has coordinates %v, want %v", want.Label(n), gc, wc)
+					break
+				}
+			}
+		}
+		checkSameAttributes(t, "node "+want.Label(n), want.NodeAttributes(n), got.NodeAttributes(gotNodes[i]))
+		for _, edge := range want.AdjacencyList[n] {
+			j, _ := want.NodeIndex(edge.Node)
+			checkSameAttributes(t, "edge "+want.Label(n)+" "+want.Label(edge.Node),
+				want.EdgeAttributes(n, edge.Node), got.EdgeAttributes(gotNodes[i], gotNodes[j]))
+		}
+	}
+	wl, gl := want.SparseLaplacian().Dense(), got.SparseLaplacian().Dense()
+	for i := range wl {
+		for j := range wl[i] {
+			if math.Abs(wl[i][j]-gl[i][j]) > 1e-15 {
+				t.Fatalf("Laplacian entry (%d, %d) is %g, want %g", i, j, gl[i][j], wl[i][j])
+			}
+		}
+	}
+}
+
+func checkSameAttributes(t *testing.T, what string, want, got map[string]Value) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Errorf("%s has attributes %v, want %v", what, got, want)
+		return
+	}
+	for key, v := range want {
+		if g, ok := got[key]; !ok || g.Kind() != v.Kind() || g.String() != v.String() {
+			t.Errorf("%s has %s = %v (%v), want %v (%v)", what, key, g, g.Kind(), v, v.Kind())
+		}
+	}
+}
+
+// checkRoundTrip writes the graph, reads it back and writes it again, which must give the same graph and file.
+func checkRoundTrip(t *testing.T, g *Graph, write func(io.Writer, *Graph) error, read func(io.Reader, ...ReadOption) (*Graph, error)) {
+	t.Helper()
+	var first, second bytes.Buffer
+	if err := write(&first, g); err != nil {
+		t.Fatal(err)
+	}
+	back, err := read(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, first.String())
+	}
+	checkSameGraph(t, g, back)
+	if err := write(&second, back); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("the file changed when written again:\n%s\nthen\n%s", first.String(), second.String())
+	}
+}
+
+func TestDuplicatePolicies(t *testing.T) {
+	// Both directions of the edge 0 - 1, then the first one again
+	const list = "0 1 2\n1 0 2\n0 1 3\n"
+	tests := []struct {
+		policy DuplicatePolicy
+		weight Weight
+		fails  bool
+	}{
+		{KeepLast, 3, false},
+		{KeepFirst, 2, false},
+		{SumWeights, 7, false},
+		{RejectDuplicates, 0, true},
+	}
+	for _, test := range tests {
+		g, err := ReadEdgeList(strings.NewReader(list), OnDuplicate(test.policy))
+		if test.fails {
+			var parseError *ParseError
+			if !errors.As(err, &parseError) || parseError.Line != 2 {
+				t.Errorf("policy %d: got %v, want an error on line 2", test.policy, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := g.AdjacencyList[0][0].Weight; w != test.weight {
+			t.Errorf("policy %d: got weight %v, want %v", test.policy, w, test.weight)
+		}
+	}
+
+	// A symmetric Matrix Market file lists each edge once, whatever the policy
+	const mtx = "%%MatrixMarket matrix coordinate real symmetric\n3 3 2\n2 1 2\n3 2 0.5\n"
+	g, err := ReadMatrixMarket(strings.NewReader(mtx), OnDuplicate(RejectDuplicates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasEdge(0, 1) || !g.HasEdge(1, 2) || g.HasEdge(0, 2) {
+		t.Errorf("got the edges %v", g.AdjacencyList)
+	}
+}
+
+func TestNumericNodeNames(t *testing.T) {
+	g, err := ReadEdgeList(strings.NewReader("7 3\n3 12\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasEdge(7, 3) || !g.HasEdge(3, 12) {
+		t.Errorf("integer names are not kept as node IDs: %v", g.AdjacencyList)
+	}
+
+	g, err = ReadEdgeList(strings.NewReader("007 7\n7 +7\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(g.Nodes()); n != 3 {
+		t.Fatalf("got %d nodes, want 007, 7 and +7 apart", n)
+	}
+	for _, name := range []string{"007", "7", "+7"} {
+		if _, ok := g.NodeByLabel(name); !ok {
+			t.Errorf("node %q is missing", name)
+		}
+	}
+}
+
+func TestAdjacencyCSVHeader(t *testing.T) {
+	// A header of integer IDs starts with the empty corner cell, and so does every row
+	g, err := ReadAdjacencyCSV(strings.NewReader(",5,7\n5,0,1.5\n7,1.5,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes := g.Nodes(); len(nodes) != 2 || nodes[0] != 5 || nodes[1] != 7 || !g.HasEdge(5, 7) {
+		t.Errorf("got the nodes %v and edges %v", nodes, g.AdjacencyList)
+	}
+
+	// Names that are not numbers need no corner cell
+	g, err = ReadAdjacencyCSV(strings.NewReader("a,b\n0,2\n2,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, okA := g.NodeByLabel("a")
+	b, okB := g.NodeByLabel("b")
+	if !okA || !okB || !g.HasEdge(a, b) {
+		t.Errorf("got the nodes %v and edges %v", g.Nodes(), g.AdjacencyList)
+	}
+
+	// Without the corner cell a header of integers is the first row of weights
+	_, err = ReadAdjacencyCSV(strings.NewReader("5,7\n0,1\n1,0\n"))
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Line != 1 {
+		t.Errorf("got %v, want an error on line 1", err)
+	}
+
+	// A header of integers is written with the corner cell, so it reads back
+	checkUnattributedRoundTrip(t, WriteAdjacencyCSV, ReadAdjacencyCSV)
+}
+
+func TestMatrixMarketNames(t *testing.T) {
+	const file = "%%MatrixMarket matrix coordinate real general\n% node 1 a\n2 2 1\n1 2 1\n"
+	_, err := ReadMatrixMarket(strings.NewReader(file))
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Line != 3 {
+		t.Errorf("got %v, want an error on line 3", err)
+	}
+	checkUnattributedRoundTrip(t, WriteMatrixMarket, ReadMatrixMarket)
+}
+
+// checkUnattributedRoundTrip runs checkRoundTrip on a graph without coordinates or attributes, which the formats
+// that only store the matrix cannot hold, and with integer node IDs in and out of order.
+func checkUnattributedRoundTrip(t *testing.T, write func(io.Writer, *Graph) error, read func(io.Reader, ...ReadOption) (*Graph, error)) {
+	t.Helper()
+	g := NewGraph()
+	g.AddEdge(3, 7, 0.5)
+	g.AddEdge(7, 1, 2)
+	g.AddEdge(1, 3, 1.25)
+	checkRoundTrip(t, g, write, read)
+
+	labeled := NewGraph()
+	labeled.AddEdge(labeled.AddLabeledNode("x"), labeled.AddLabeledNode("y"), 3)
+	checkRoundTrip(t, labeled, write, read)
+}
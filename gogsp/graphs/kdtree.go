@@ -0,0 +1,160 @@
+// kdtree.go contains the k-d tree behind the nearest-neighbour graph builders.
+// The tree splits the points on the median of the widest coordinate, so that a query visits O(log N) leaves on
+// well-spread data instead of comparing against all N points.
+
+package graphs
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// kdLeafSize is the number of points below which a node of the tree is not split any further.
+const kdLeafSize = 8
+
+type kdTree struct {
+	points [][]float64
+	root   *kdNode
+}
+
+type kdNode struct {
+	// indices holds the points of a leaf, nil for inner nodes
+	indices []int
+	// axis and split define the hyperplane of an inner node: left has coordinate axis <= split
+	axis        int
+	split       float64
+	left, right *kdNode
+}
+
+func newKDTree(points [][]float64) *kdTree {
+	indices := make([]int, len(points))
+	for i := range indices {
+		indices[i] = i
+	}
+	return &kdTree{points: points, root: buildKD(points, indices)}
+}
+
+func buildKD(points [][]float64, indices []int) *kdNode {
+	if len(indices) <= kdLeafSize {
+		return &kdNode{indices: indices}
+	}
+	// Split along the coordinate with the widest spread
+	axis, spread := 0, -1.0
+	for d := range points[indices[0]] {
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, i := range indices {
+			low = math.Min(low, points[i][d])
+			high = math.Max(high, points[i][d])
+		}
+		if high-low > spread {
+			axis, spread = d, high-low
+		}
+	}
+	if spread == 0 {
+		// All points coincide
+		return &kdNode{indices: indices}
+	}
+	sort.Slice(indices, func(a, b int) bool { return points[indices[a]][axis] < points[indices[b]][axis] })
+	middle := len(indices) / 2
+	// The split must be read before the children reorder their halves
+	node := &kdNode{axis: axis, split: points[indices[middle-1]][axis]}
+	node.left = buildKD(points, indices[:middle])
+	node.right = buildKD(points, indices[middle:])
+	return node
+}
+
+// neighbour is a point found by a query with its distance to the query point.
+type neighbour struct {
+	index    int
+	distance float64
+}
+
+// farthestFirst is a max-heap of neighbours on distance, holding the k best candidates of a query.
+type farthestFirst []neighbour
+
+func (h farthestFirst) Len() int            { return len(h) }
+func (h farthestFirst) Less(i, j int) bool  { return h[i].distance > h[j].distance }
+func (h farthestFirst) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *farthestFirst) Push(x interface{}) { *h = append(*h, x.(neighbour)) }
+func (h *farthestFirst) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// nearest returns the k points closest to point i, excluding i itself, by increasing distance.
+func (t *kdTree) nearest(i, k int) []neighbour {
+	best := &farthestFirst{}
+	var search func(node *kdNode)
+	search = func(node *kdNode) {
+		if node.indices != nil {
+			for _, j := range node.indices {
+				if j == i {
+					continue
+				}
+				d := distance(t.points[i], t.points[j])
+				if best.Len() < k {
+					heap.Push(best, neighbour{j, d})
+				} else if d < (*best)[0].distance {
+					(*best)[0] = neighbour{j, d}
+					heap.Fix(best, 0)
+				}
+			}
+			return
+		}
+		offset := t.points[i][node.axis] - node.split
+		near, far := node.left, node.right
+		if offset > 0 {
+			near, far = far, near
+		}
+		search(near)
+		if best.Len() < k || math.Abs(offset) <= (*best)[0].distance {
+			search(far)
+		}
+	}
+	search(t.root)
+
+	result := make([]neighbour, best.Len())
+	for j := len(result) - 1; j >= 0; j-- {
+		result[j] = heap.Pop(best).(neighbour)
+	}
+	return result
+}
+
+// within returns the points at distance at most radius from point i, excluding i itself, in no particular order.
+func (t *kdTree) within(i int, radius float64) []neighbour {
+	var result []neighbour
+	var search func(node *kdNode)
+	search = func(node *kdNode) {
+		if node.indices != nil {
+			for _, j := range node.indices {
+				if j == i {
+					continue
+				}
+				if d := distance(t.points[i], t.points[j]); d <= radius {
+					result = append(result, neighbour{j, d})
+				}
+			}
+			return
+		}
+		offset := t.points[i][node.axis] - node.split
+		if offset <= radius {
+			search(node.left)
+		}
+		if offset >= -radius {
+			search(node.right)
+		}
+	}
+	search(t.root)
+	return result
+}
+
+func distance(a, b []float64) float64 {
+	sum := 0.0
+	for d := range a {
+		sum += (a[d] - b[d]) * (a[d] - b[d])
+	}
+	return math.Sqrt(sum)
+}
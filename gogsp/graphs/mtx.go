@@ -0,0 +1,201 @@
+// mtx.go contains the Matrix Market exchange format for the weight matrix, in its sparse coordinate form.
+// Matrix Market indexes rows from 1 and has no node names, so row i is node i-1 unless the file carries
+// "% node <row> <name>" comment lines, which the writer adds whenever the node IDs are not 0..N-1 in matrix order.
+// Symmetric files list one triangle, whose entries are mirrored when the graph is directed. General files list every
+// entry; read as an undirected graph their (i, j) and (j, i) entries are the same edge, see DuplicatePolicy.
+
+package graphs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadMatrixMarket reads a square coordinate matrix of real, integer or pattern values, general or symmetric.
+// Pattern entries have weight 1.
+func ReadMatrixMarket(r io.Reader, opts ...ReadOption) (*Graph, error) {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	next := func() (string, bool) {
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text != "" {
+				return text, true
+			}
+		}
+		return "", false
+	}
+
+	header, ok := next()
+	if !ok {
+		return nil, parseErrorf(line, "empty file")
+	}
+	banner := strings.Fields(strings.ToLower(header))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return nil, parseErrorf(line, "not a Matrix Market matrix header: %q", header)
+	}
+	if banner[2] != "coordinate" {
+		return nil, parseErrorf(line, "unsupported format %q, only coordinate matrices are read", banner[2])
+	}
+	field, symmetry := banner[3], banner[4]
+	if field != "real" && field != "integer" && field != "pattern" {
+		return nil, parseErrorf(line, "unsupported field %q, expected real, integer or pattern", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return nil, parseErrorf(line, "unsupported symmetry %q, expected general or symmetric", symmetry)
+	}
+
+	// Comments, with the optional node names, come before the size line
+	names := make(map[int]string)
+	var text string
+	for {
+		text, ok = next()
+		if !ok {
+			return nil, parseErrorf(line, "missing size line")
+		}
+		if text[0] != '%' {
+			break
+		}
+		fields := strings.Fields(strings.TrimPrefix(text, "%"))
+		if len(fields) == 3 && fields[0] == "node" {
+			row, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, parseErrorf(line, "invalid node row %q", fields[1])
+			}
+			names[row] = fields[2]
+		}
+	}
+	size := strings.Fields(text)
+	if len(size) != 3 {
+		return nil, parseErrorf(line, "expected \"rows columns entries\", got %q", text)
+	}
+	var dims [3]int
+	for k := range dims {
+		v, err := strconv.Atoi(size[k])
+		if err != nil || v < 0 {
+			return nil, parseErrorf(line, "invalid size %q", size[k])
+		}
+		dims[k] = v
+	}
+	n, entries := dims[0], dims[2]
+	if dims[1] != n {
+		return nil, parseErrorf(line, "an adjacency matrix must be square, got %d x %d", n, dims[1])
+	}
+
+	b := newBuilder(newReadConfig(opts))
+	nodes := make([]Node, n)
+	if len(names) > 0 {
+		list := make([]string, n)
+		for row := 1; row <= n; row++ {
+			name, ok := names[row]
+			if !ok {
+				return nil, parseErrorf(line, "node names are given for %d of the %d rows, row %d has none", len(names), n, row)
+			}
+			list[row-1] = name
+		}
+		resolver := newNodeNames(b.graph, list)
+		for i, name := range list {
+			nodes[i] = resolver.node(name)
+		}
+	} else {
+		for i := range nodes {
+			nodes[i] = Node(i)
+			b.graph.AddNode(nodes[i])
+		}
+	}
+
+	for k := 0; k < entries; k++ {
+		text, ok = next()
+		if !ok {
+			return nil, parseErrorf(line, "expected %d entries, found %d", entries, k)
+		}
+		fields := strings.Fields(text)
+		want := 3
+		if field == "pattern" {
+			want = 2
+		}
+		if len(fields) != want {
+			return nil, parseErrorf(line, "expected %d fields, got %d", want, len(fields))
+		}
+		i, err1 := strconv.Atoi(fields[0])
+		j, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil || i < 1 || i > n || j < 1 || j > n {
+			return nil, parseErrorf(line, "invalid position (%s, %s) in a %d x %d matrix", fields[0], fields[1], n, n)
+		}
+		weight := Weight(1)
+		if field != "pattern" {
+			if weight, err1 = parseWeight(line, fields[2]); err1 != nil {
+				return nil, err1
+			}
+		}
+		if err := b.edge(line, nodes[i-1], nodes[j-1], weight); err != nil {
+			return nil, err
+		}
+		if symmetry == "symmetric" && b.graph.IsDirected() && i != j {
+			if err := b.edge(line, nodes[j-1], nodes[i-1], weight); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if text, ok = next(); ok {
+		return nil, parseErrorf(line, "unexpected data after %d entries: %q", entries, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b.graph, nil
+}
+
+// WriteMatrixMarket writes the weight matrix of the graph as a real coordinate matrix, symmetric with the lower
+// triangle only for undirected graphs and general for directed ones. Rows follow the matrix order of the graph.
+// Matrix Market only holds the matrix, so coordinates and attributes are not written.
+func WriteMatrixMarket(w io.Writer, g *Graph) error {
+	nodes := g.Nodes()
+	symmetry := "symmetric"
+	if g.IsDirected() {
+		symmetry = "general"
+	}
+	entries := 0
+	_ = edges(g, func(Node, Node, Weight) error {
+		entries++
+		return nil
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real %s\n", symmetry)
+	named := false
+	for i, n := range nodes {
+		if g.Label(n) != strconv.Itoa(i) {
+			named = true
+			break
+		}
+	}
+	if named {
+		for i, n := range nodes {
+			label := g.Label(n)
+			if label == "" || strings.ContainsAny(label, " \t\r\n") {
+				return fmt.Errorf("node label %q cannot be written in a Matrix Market comment", label)
+			}
+			fmt.Fprintf(bw, "%% node %d %s\n", i+1, label)
+		}
+	}
+	fmt.Fprintf(bw, "%d %d %d\n", len(nodes), len(nodes), entries)
+	err := edges(g, func(u, v Node, weight Weight) error {
+		i, _ := g.NodeIndex(u)
+		j, _ := g.NodeIndex(v)
+		if !g.IsDirected() && i < j {
+			// Symmetric files store the lower triangle
+			i, j = j, i
+		}
+		_, err := fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, formatWeight(weight))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,188 @@
+// nngraph.go contains the construction of graphs from point clouds, such as sensor positions or feature vectors.
+// Every row of an N x d matrix becomes a node, linked either to its k nearest neighbours or to every point within
+// a radius ε, with a Gaussian weight that decays with the distance. The neighbours are found with a k-d tree.
+// The rows are kept as the node coordinates, so the graph can be drawn when d is 2 or 3.
+
+package graphs
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Symmetrization decides which kNN relations become edges, since j being among the neighbours of i does not imply
+// the converse.
+type Symmetrization int
+
+const (
+	// Union links i and j when either is among the nearest neighbours of the other
+	Union Symmetrization = iota
+	// Mutual links i and j only when each is among the nearest neighbours of the other
+	Mutual
+)
+
+// nnConfig holds the settings of the nearest-neighbour builders.
+type nnConfig struct {
+	sigma          float64
+	adaptive       int
+	symmetrization Symmetrization
+	graph          []GraphOption
+}
+
+// NNOption configures KNNGraph and RadiusGraph.
+type NNOption func(*nnConfig)
+
+// WithSigma sets the width of the Gaussian kernel, w(i, j) = exp(-d(i, j)² / 2σ²). By default σ is the mean
+// distance between linked points.
+func WithSigma(sigma float64) NNOption {
+	return func(c *nnConfig) {
+		c.sigma = sigma
+	}
+}
+
+// AdaptiveSigma uses the self-tuning kernel of Zelnik-Manor and Perona, w(i, j) = exp(-d(i, j)² / σ_i σ_j), where
+// σ_i is the distance from i to its k-th nearest neighbour. It adapts the kernel to regions of varying density.
+func AdaptiveSigma(k int) NNOption {
+	return func(c *nnConfig) {
+		c.adaptive = k
+	}
+}
+
+// WithSymmetrization chooses how the kNN relation is symmetrized, Union by default. It has no effect on
+// RadiusGraph, whose relation is symmetric.
+func WithSymmetrization(s Symmetrization) NNOption {
+	return func(c *nnConfig) {
+		c.symmetrization = s
+	}
+}
+
+// WithNNGraphOptions passes options, such as WithLaplacian, to NewGraph. With Directed every edge is stored as the
+// two arcs i -> j and j -> i, since the symmetrized relation has no direction.
+func WithNNGraphOptions(opts ...GraphOption) NNOption {
+	return func(c *nnConfig) {
+		c.graph = append(c.graph, opts...)
+	}
+}
+
+// KNNGraph links every row of the features to its k nearest rows in Euclidean distance. Node i is row i.
+func KNNGraph(features mat.Matrix, k int, opts ...NNOption) (*Graph, error) {
+	n, _ := features.Dims()
+	if k < 1 || k >= n {
+		return nil, fmt.Errorf("k must be in [1, %d) for %d points, got %d", n, n, k)
+	}
+	return nearestNeighbours(features, opts, func(t *kdTree, i int) []neighbour {
+		return t.nearest(i, k)
+	})
+}
+
+// RadiusGraph links every pair of rows of the features within Euclidean distance epsilon. Node i is row i.
+// Points without any neighbour within epsilon become isolated nodes.
+func RadiusGraph(features mat.Matrix, epsilon float64, opts ...NNOption) (*Graph, error) {
+	if epsilon <= 0 {
+		return nil, fmt.Errorf("radius must be positive, got %v", epsilon)
+	}
+	return nearestNeighbours(features, opts, func(t *kdTree, i int) []neighbour {
+		return t.within(i, epsilon)
+	})
+}
+
+func nearestNeighbours(features mat.Matrix, opts []NNOption, query func(*kdTree, int) []neighbour) (*Graph, error) {
+	c := &nnConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	n, d := features.Dims()
+	if n < 2 || d < 1 {
+		return nil, fmt.Errorf("need at least 2 points with 1 coordinate, got a %d x %d matrix", n, d)
+	}
+	if c.sigma < 0 {
+		return nil, fmt.Errorf("sigma must be positive, got %v", c.sigma)
+	}
+	if c.adaptive < 0 || c.adaptive >= n {
+		return nil, fmt.Errorf("adaptive sigma needs a neighbour rank in [1, %d), got %d", n, c.adaptive)
+	}
+
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = mat.Row(nil, i, features)
+		for _, x := range points[i] {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return nil, fmt.Errorf("point %d has a non-finite coordinate", i)
+			}
+		}
+	}
+	tree := newKDTree(points)
+
+	// Count every relation i -> j, an edge is kept when it was found in one or both directions
+	type pair struct{ i, j int }
+	found := make(map[pair]int)
+	distances := make(map[pair]float64)
+	var order []pair
+	for i := 0; i < n; i++ {
+		for _, nb := range query(tree, i) {
+			p := pair{i, nb.index}
+			if p.i > p.j {
+				p = pair{p.j, p.i}
+			}
+			if found[p] == 0 {
+				order = append(order, p)
+				distances[p] = nb.distance
+			}
+			found[p]++
+		}
+	}
+	kept := order[:0]
+	for _, p := range order {
+		if c.symmetrization == Union || found[p] == 2 {
+			kept = append(kept, p)
+		}
+	}
+
+	// σ_i for the adaptive kernel, or a global σ
+	var local []float64
+	if c.adaptive > 0 {
+		local = make([]float64, n)
+		for i := range local {
+			nbs := tree.nearest(i, c.adaptive)
+			local[i] = nbs[len(nbs)-1].distance
+		}
+	}
+	sigma := c.sigma
+	if sigma == 0 && local == nil {
+		for _, p := range kept {
+			sigma += distances[p]
+		}
+		if len(kept) > 0 {
+			sigma /= float64(len(kept))
+		}
+	}
+
+	g := NewGraph(c.graph...)
+	for i := 0; i < n; i++ {
+		g.AddNode(Node(i))
+		// Every node gets the same number of coordinates, so this cannot fail
+		_ = g.SetCoordinates(Node(i), points[i]...)
+	}
+	for _, p := range kept {
+		dist := distances[p]
+		var w float64
+		switch {
+		case dist == 0:
+			w = 1
+		case local != nil:
+			w = math.Exp(-dist * dist / (local[p.i] * local[p.j]))
+		case sigma > 0:
+			w = math.Exp(-dist * dist / (2 * sigma * sigma))
+		}
+		// Pairs whose weight underflows, or whose kernel has zero width, are left out
+		if w > 0 && !math.IsNaN(w) {
+			g.AddEdge(Node(p.i), Node(p.j), Weight(w))
+			if g.IsDirected() {
+				g.AddEdge(Node(p.j), Node(p.i), Weight(w))
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,132 @@
+package graphs
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// randomPoints returns n points uniform in the unit cube of dimension d.
+func randomPoints(n, d int, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	points := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for k := 0; k < d; k++ {
+			points.Set(i, k, rng.Float64())
+		}
+	}
+	return points
+}
+
+// bruteForce returns the distances from point i to every other point, sorted, with their indices.
+func bruteForce(points [][]float64, i int) []neighbour {
+	var all []neighbour
+	for j := range points {
+		if j != i {
+			all = append(all, neighbour{j, distance(points[i], points[j])})
+		}
+	}
+	sort.Slice(all, func(a, b int) bool { return all[a].distance < all[b].distance })
+	return all
+}
+
+func TestKDTree(t *testing.T) {
+	for _, d := range []int{1, 2, 5} {
+		features := randomPoints(300, d, int64(d))
+		points := make([][]float64, 300)
+		for i := range points {
+			points[i] = mat.Row(nil, i, features)
+		}
+		tree := newKDTree(points)
+		for i := range points {
+			all := bruteForce(points, i)
+
+			nearest := tree.nearest(i, 7)
+			for k, nb := range nearest {
+				if nb != all[k] {
+					t.Fatalf("d = %d: neighbour %d of point %d is %+v, want %+v", d, k, i, nb, all[k])
+				}
+			}
+
+			radius := all[10].distance
+			within := tree.within(i, radius)
+			sort.Slice(within, func(a, b int) bool { return within[a].distance < within[b].distance })
+			if len(within) != 11 {
+				t.Fatalf("d = %d: got %d points within %g of point %d, want 11", d, len(within), radius, i)
+			}
+			for k, nb := range within {
+				if nb != all[k] {
+					t.Fatalf("d = %d: point %d within the radius of point %d is %+v, want %+v", d, k, i, nb, all[k])
+				}
+			}
+		}
+	}
+}
+
+func TestKNNGraph(t *testing.T) {
+	features := randomPoints(200, 2, 1)
+	union, err := KNNGraph(features, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutual, err := KNNGraph(features, 5, WithSymmetrization(Mutual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutualEdges, unionEdges := 0, 0
+	for n, edges := range mutual.AdjacencyList {
+		for _, edge := range edges {
+			mutualEdges++
+			if !union.HasEdge(n, edge.Node) {
+				t.Errorf("the mutual edge %v - %v is missing from the union", n, edge.Node)
+			}
+		}
+	}
+	for n := range union.AdjacencyList {
+		// Every node keeps its k nearest neighbours in the union
+		if len(union.AdjacencyList[n]) < 5 {
+			t.Errorf("node %v has %d neighbours in the union, want at least 5", n, len(union.AdjacencyList[n]))
+		}
+		unionEdges += len(union.AdjacencyList[n])
+	}
+	if mutualEdges >= unionEdges {
+		t.Errorf("the mutual graph has %d arcs and the union %d", mutualEdges, unionEdges)
+	}
+
+	// A directed graph gets both arcs of every edge
+	directed, err := KNNGraph(features, 5, WithNNGraphOptions(Directed(OutDegree)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, edges := range union.AdjacencyList {
+		for _, edge := range edges {
+			if !directed.HasEdge(n, edge.Node) || !directed.HasEdge(edge.Node, n) {
+				t.Errorf("the directed graph misses an arc of the edge %v - %v", n, edge.Node)
+			}
+		}
+	}
+}
+
+func TestRadiusGraph(t *testing.T) {
+	features := randomPoints(150, 3, 2)
+	g, err := RadiusGraph(features, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := make([][]float64, 150)
+	for i := range points {
+		points[i] = mat.Row(nil, i, features)
+	}
+	for i := range points {
+		for j := range points {
+			if i == j {
+				continue
+			}
+			if want := distance(points[i], points[j]) <= 0.2; g.HasEdge(Node(i), Node(j)) != want {
+				t.Errorf("edge %d - %d is present %t, want %t", i, j, !want, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// random.go contains the random graph models: Erdős–Rényi, Barabási–Albert preferential attachment, Watts–Strogatz
+// small worlds, stochastic block models and uniform random spanning trees.
+// Every generator draws from the *rand.Rand it is given, so a graph is reproduced exactly by passing
+// rand.New(rand.NewSource(seed)) with the same seed. Nodes are numbered from 0 in matrix order.
+
+package graphs
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// WeightDistribution draws the weight of a new edge.
+type WeightDistribution func(rng *rand.Rand) Weight
+
+// ConstantWeight gives every edge the same weight.
+func ConstantWeight(w Weight) WeightDistribution {
+	return func(*rand.Rand) Weight {
+		return w
+	}
+}
+
+// UniformWeight draws weights uniformly from [low, high).
+func UniformWeight(low, high Weight) WeightDistribution {
+	return func(rng *rand.Rand) Weight {
+		return low + (high-low)*Weight(rng.Float64())
+	}
+}
+
+// ExponentialWeight draws weights from the exponential distribution with the given mean.
+func ExponentialWeight(mean Weight) WeightDistribution {
+	return func(rng *rand.Rand) Weight {
+		return mean * Weight(rng.ExpFloat64())
+	}
+}
+
+// randomConfig holds the settings shared by the random generators.
+type randomConfig struct {
+	weights   WeightDistribution
+	connected bool
+	graph     []GraphOption
+}
+
+// RandomOption configures a random graph generator.
+type RandomOption func(*randomConfig)
+
+// WithWeights draws the edge weights from the distribution instead of giving every edge weight 1.
+func WithWeights(weights WeightDistribution) RandomOption {
+	return func(c *randomConfig) {
+		c.weights = weights
+	}
+}
+
+// Connected guarantees a connected graph, weakly connected if it is directed: after sampling, every connected
+// component but the first is linked to a random node of the others by one extra edge. This slightly departs from the
+// model, by at most one edge per component.
+func Connected() RandomOption {
+	return func(c *randomConfig) {
+		c.connected = true
+	}
+}
+
+// WithGraphOptions passes options, such as WithLaplacian, to NewGraph.
+func WithGraphOptions(opts ...GraphOption) RandomOption {
+	return func(c *randomConfig) {
+		c.graph = append(c.graph, opts...)
+	}
+}
+
+// newRandom validates the shared arguments and returns the configuration and a graph with nodes 0..n-1.
+func newRandom(n int, rng *rand.Rand, opts []RandomOption) (*randomConfig, *Graph, error) {
+	if rng == nil {
+		return nil, nil, fmt.Errorf("random graph generators need a *rand.Rand")
+	}
+	if n < 1 {
+		return nil, nil, fmt.Errorf("a random graph needs at least 1 node, got %d", n)
+	}
+	c := &randomConfig{weights: ConstantWeight(1)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	g := NewGraph(c.graph...)
+	for i := 0; i < n; i++ {
+		g.AddNode(Node(i))
+	}
+	return c, g, nil
+}
+
+// link adds the edge between i and j with a weight drawn from the configuration.
+func (c *randomConfig) link(g *Graph, rng *rand.Rand, i, j int) {
+	g.AddEdge(Node(i), Node(j), c.weights(rng))
+}
+
+// pairs calls fn for every pair of distinct nodes the models may link: i < j in an undirected graph, and both (i, j)
+// and (j, i) in a directed one, whose two arcs are drawn independently.
+func pairs(g *Graph, n int, fn func(i, j int)) {
+	for i := 0; i < n; i++ {
+		first := i + 1
+		if g.IsDirected() {
+			first = 0
+		}
+		for j := first; j < n; j++ {
+			if j != i {
+				fn(i, j)
+			}
+		}
+	}
+}
+
+// finish connects the graph if the configuration asks for it.
+func (c *randomConfig) finish(g *Graph, rng *rand.Rand) *Graph {
+	if !c.connected {
+		return g
+	}
+	n := len(g.AdjacencyList)
+	uf := NewUnionFind(n)
+	for node, edges := range g.AdjacencyList {
+		for _, edge := range edges {
+			uf.Union(node, edge.Node)
+		}
+	}
+	// Components in order of their smallest node, so that the result only depends on the seed
+	var components [][]int
+	position := make(map[Node]int)
+	for i := 0; i < n; i++ {
+		root := uf.Find(Node(i))
+		k, ok := position[root]
+		if !ok {
+			k = len(components)
+			position[root] = k
+			components = append(components, nil)
+		}
+		components[k] = append(components[k], i)
+	}
+	for k := 1; k < len(components); k++ {
+		earlier := components[rng.Intn(k)]
+		c.link(g, rng, components[k][rng.Intn(len(components[k]))], earlier[rng.Intn(len(earlier))])
+	}
+	return g
+}
+
+// ErdosRenyi returns a G(n, p) graph, in which each of the n(n-1)/2 possible edges is present independently with
+// probability p. A directed graph draws each of the n(n-1) possible arcs independently instead.
+func ErdosRenyi(n int, p float64, rng *rand.Rand, opts ...RandomOption) (*Graph, error) {
+	if p < 0 || p > 1 {
+		return nil, fmt.Errorf("edge probability must be in [0, 1], got %v", p)
+	}
+	c, g, err := newRandom(n, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+	pairs(g, n, func(i, j int) {
+		if rng.Float64() < p {
+			c.link(g, rng, i, j)
+		}
+	})
+	return c.finish(g, rng), nil
+}
+
+// BarabasiAlbert returns a preferential attachment graph. It starts from a star on m+1 nodes, and every further
+// node is linked to m distinct existing nodes chosen with probability proportional to their degree, which gives a
+// power-law degree distribution. The graph is always connected. The model is undirected, so Directed graph options
+// are rejected.
+func BarabasiAlbert(n, m int, rng *rand.Rand, opts ...RandomOption) (*Graph, error) {
+	if m < 1 || n <= m {
+		return nil, fmt.Errorf("preferential attachment needs 1 <= m < n, got m = %d and n = %d", m, n)
+	}
+	c, g, err := newRandom(n, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+	if g.IsDirected() {
+		return nil, fmt.Errorf("preferential attachment graphs are undirected, drop the Directed option")
+	}
+	// Every node appears in repeated once per edge end, so a uniform draw from it is proportional to degree
+	repeated := make([]int, 0, 2*m*n)
+	for i := 1; i <= m; i++ {
+		c.link(g, rng, 0, i)
+		repeated = append(repeated, 0, i)
+	}
+	for i := m + 1; i < n; i++ {
+		targets := make(map[int]bool, m)
+		chosen := make([]int, 0, m)
+		for len(chosen) < m {
+			t := repeated[rng.Intn(len(repeated))]
+			if !targets[t] {
+				targets[t] = true
+				chosen = append(chosen, t)
+			}
+		}
+		for _, t := range chosen {
+			c.link(g, rng, i, t)
+			repeated = append(repeated, i, t)
+		}
+	}
+	return c.finish(g, rng), nil
+}
+
+// WattsStrogatz returns a small-world graph: a ring on n nodes, each linked to its k nearest neighbours (k even),
+// in which every edge is rewired with probability beta to a uniformly chosen node, avoiding self-loops and
+// duplicate edges. The model is undirected, so Directed graph options are rejected.
+func WattsStrogatz(n, k int, beta float64, rng *rand.Rand, opts ...RandomOption) (*Graph, error) {
+	if k < 2 || k%2 != 0 || k >= n {
+		return nil, fmt.Errorf("small-world graphs need an even k with 2 <= k < n, got k = %d and n = %d", k, n)
+	}
+	if beta < 0 || beta > 1 {
+		return nil, fmt.Errorf("rewiring probability must be in [0, 1], got %v", beta)
+	}
+	c, g, err := newRandom(n, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+	if g.IsDirected() {
+		return nil, fmt.Errorf("small-world graphs are undirected, drop the Directed option")
+	}
+	for i := 0; i < n; i++ {
+		for d := 1; d <= k/2; d++ {
+			c.link(g, rng, i, (i+d)%n)
+		}
+	}
+	for d := 1; d <= k/2; d++ {
+		for i := 0; i < n; i++ {
+			j := (i + d) % n
+			if rng.Float64() >= beta || !g.HasEdge(Node(i), Node(j)) {
+				continue
+			}
+			// A node already linked to every other one keeps its edge
+			if len(g.AdjacencyList[Node(i)]) >= n-1 {
+				continue
+			}
+			t := rng.Intn(n)
+			for t == i || g.HasEdge(Node(i), Node(t)) {
+				t = rng.Intn(n)
+			}
+			g.RemoveEdge(Node(i), Node(j))
+			c.link(g, rng, i, t)
+		}
+	}
+	return c.finish(g, rng), nil
+}
+
+// StochasticBlockModel returns a graph whose nodes are split into blocks of the given sizes, where nodes of blocks
+// a and b are linked with probability p[a][b]. The matrix p must be symmetric, unless the graph is directed, in which
+// case the arc from a node of block a to a node of block b is drawn with probability p[a][b]. Nodes are numbered
+// block after block, and the second result holds the block of every node in matrix order, the ground-truth
+// communities.
+func StochasticBlockModel(sizes []int, p [][]float64, rng *rand.Rand, opts ...RandomOption) (*Graph, []int, error) {
+	if len(p) != len(sizes) {
+		return nil, nil, fmt.Errorf("got %d block sizes but a %d x _ probability matrix", len(sizes), len(p))
+	}
+	n := 0
+	for a, size := range sizes {
+		if size < 1 {
+			return nil, nil, fmt.Errorf("block %d must have at least 1 node, got %d", a, size)
+		}
+		if len(p[a]) != len(sizes) {
+			return nil, nil, fmt.Errorf("row %d of the probability matrix has %d entries, want %d", a, len(p[a]), len(sizes))
+		}
+		for b := range p[a] {
+			if p[a][b] < 0 || p[a][b] > 1 {
+				return nil, nil, fmt.Errorf("probability between blocks %d and %d must be in [0, 1], got %v", a, b, p[a][b])
+			}
+		}
+		n += size
+	}
+	c, g, err := newRandom(n, rng, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !g.IsDirected() {
+		for a := range p {
+			for b := range p[a] {
+				if p[a][b] != p[b][a] {
+					return nil, nil, fmt.Errorf("probability matrix is not symmetric at blocks %d and %d", a, b)
+				}
+			}
+		}
+	}
+
+	blocks := make([]int, 0, n)
+	for a, size := range sizes {
+		for i := 0; i < size; i++ {
+			blocks = append(blocks, a)
+		}
+	}
+	pairs(g, n, func(i, j int) {
+		if rng.Float64() < p[blocks[i]][blocks[j]] {
+			c.link(g, rng, i, j)
+		}
+	})
+	return c.finish(g, rng), blocks, nil
+}
+
+// RandomSpanningTree returns a random tree on n nodes: the minimum spanning tree of the complete graph under
+// independent uniform edge priorities, built with Kruskal's algorithm.
+func RandomSpanningTree(n int, rng *rand.Rand, opts ...RandomOption) (*Graph, error) {
+	c, g, err := newRandom(n, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct {
+		node1, node2 Node
+	}
+
+	// Generate all possible edges and shuffle them randomly
+	edges := make([]pair, 0, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			edges = append(edges, pair{Node(i), Node(j)})
+		}
+	}
+	rng.Shuffle(len(edges), func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+	})
+
+	uf := NewUnionFind(n)
+	for _, edge := range edges {
+		if uf.Find(edge.node1) != uf.Find(edge.node2) {
+			uf.Union(edge.node1, edge.node2)
+			c.link(g, rng, int(edge.node1), int(edge.node2))
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,115 @@
+package graphs
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// arcs returns the number of entries of the adjacency lists, two per undirected edge.
+func arcs(g *Graph) int {
+	count := 0
+	for _, list := range g.AdjacencyList {
+		count += len(list)
+	}
+	return count
+}
+
+func TestErdosRenyiDirected(t *testing.T) {
+	g, err := ErdosRenyi(6, 1, rand.New(rand.NewSource(1)), WithGraphOptions(Directed(OutDegree)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := arcs(g); n != 30 {
+		t.Errorf("the complete directed G(6, 1) has %d arcs, want 30", n)
+	}
+
+	// Arcs in both directions are drawn independently, so some pairs are linked one way only
+	g, err = ErdosRenyi(40, 0.5, rand.New(rand.NewSource(1)), WithGraphOptions(Directed(OutDegree)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forward, backward, oneWay := 0, 0, 0
+	for i := 0; i < 40; i++ {
+		for j := 0; j < 40; j++ {
+			if !g.HasEdge(Node(i), Node(j)) {
+				continue
+			}
+			if i < j {
+				forward++
+			} else {
+				backward++
+			}
+			if !g.HasEdge(Node(j), Node(i)) {
+				oneWay++
+			}
+		}
+	}
+	if forward < 300 || backward < 300 || oneWay == 0 {
+		t.Errorf("got %d arcs i -> j with i < j, %d with i > j and %d without their reverse", forward, backward, oneWay)
+	}
+}
+
+func TestStochasticBlockModelDirected(t *testing.T) {
+	// Arcs only go from block 0 to block 1
+	p := [][]float64{{0, 1}, {0, 0}}
+	g, blocks, err := StochasticBlockModel([]int{3, 4}, p, rand.New(rand.NewSource(1)), WithGraphOptions(Directed(OutDegree)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := arcs(g); n != 12 {
+		t.Errorf("got %d arcs, want 12", n)
+	}
+	for node, edges := range g.AdjacencyList {
+		for _, edge := range edges {
+			if blocks[node] != 0 || blocks[edge.Node] != 1 {
+				t.Errorf("arc %v -> %v goes from block %d to block %d", node, edge.Node, blocks[node], blocks[edge.Node])
+			}
+		}
+	}
+
+	if _, _, err := StochasticBlockModel([]int{3, 4}, p, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("an undirected model accepts an asymmetric probability matrix")
+	}
+}
+
+func TestRandomGraphsAreReproducible(t *testing.T) {
+	a, err := ErdosRenyi(30, 0.2, rand.New(rand.NewSource(7)), Connected())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ErdosRenyi(30, 0.2, rand.New(rand.NewSource(7)), Connected())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSameGraph(t, a, b)
+	if !a.IsFullyConnected() {
+		t.Error("the graph is not connected")
+	}
+}
+
+func TestUndirectedModels(t *testing.T) {
+	directed := WithGraphOptions(Directed(OutDegree))
+	if _, err := BarabasiAlbert(20, 2, rand.New(rand.NewSource(1)), directed); err == nil {
+		t.Error("a directed preferential attachment graph is built")
+	}
+	if _, err := WattsStrogatz(20, 4, 0.1, rand.New(rand.NewSource(1)), directed); err == nil {
+		t.Error("a directed small-world graph is built")
+	}
+
+	// m edges for each node after the first m+1, and m for the star
+	g, err := BarabasiAlbert(50, 3, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := arcs(g); n != 2*3*(50-3) || !g.IsFullyConnected() {
+		t.Errorf("got %d arcs and connectedness %t, want %d arcs and a connected graph", n, g.IsFullyConnected(), 2*3*47)
+	}
+	// Rewiring keeps the n k / 2 edges of the ring lattice
+	g, err = WattsStrogatz(30, 4, 0.3, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := arcs(g); n != 30*4 {
+		t.Errorf("got %d arcs, want %d", n, 30*4)
+	}
+}
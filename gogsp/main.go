@@ -1,91 +1,93 @@
+// Command gogsp runs the graph signal processing of the library on graph and signal files.
+//
+// Usage:
+//
+//	gogsp <command> [flags] <graph> [signal]
+//
+// The commands are:
+//
+//	info     print the size, degrees, connectivity and spectrum bounds of a graph
+//	convert  write a graph in another file format
+//	gft      graph Fourier transform of signals
+//	igft     inverse graph Fourier transform of coefficients
+//	filter   filter signals with a heat, low-pass or high-pass kernel
+//	wavelet  spectral graph wavelet transform of a signal
+//	plot     render a graph, or a signal on it, as PNG or Graphviz DOT
+//
+// File formats are chosen by extension. Graphs are read from edge lists (.txt, .edges), edge CSV (.csv), Matrix
+// Market (.mtx), GraphML (.graphml) and GML (.gml), or dense adjacency CSV with -graph-format adjacency.
+// Signals are read from CSV, JSON, .npy and .npz files, with a column of values per signal; see the signals package
+// for the layouts. Results are written as CSV to standard output unless -o names a file.
 package main
 
 import (
-	"example/gogsp/filters"
-	"example/gogsp/graphs"
-	"example/gogsp/signals"
+	"errors"
+	"flag"
 	"fmt"
-	"math/rand"
-	"time"
+	"os"
 )
 
-func main() {
-	// Setting seed for randomness
-	rand.Seed(time.Now().UnixNano())
-
-	// Create a random 7 node graph
-	g := graphs.RandomWeightedGraph(7)
-
-	// Print the graph
-	g.PrintGraph()
-	fmt.Println()
-
-	// Print the weighted graph
-	g.UpdateWeightedGraph()
-	g.PrintWeightedGraph()
-	fmt.Println()
-
-	// Print Laplacian matrix
-	g.UpdateLaplacianMatrix()
-	g.PrintLaplacianMatrix()
-	fmt.Println()
-
-	// Create a signal on the graph
-	signal := signals.CreateSignal(7)
-	signal.SetSignal(g, []float64{3, 4, 5, 7, 11, 13, 17})
-
-	// Print original signal
-	fmt.Println("Original signal:")
-	signal.PrintSignal()
+// command is a subcommand of gogsp.
+type command struct {
+	name, args, summary string
+	run                 func(args []string) error
+}
 
-	// Compute Graph Fourier Transform
-	transformedSignal, err := signal.GraphFourierTransform(g)
-	if err != nil {
-		fmt.Println(err)
+var commands []command
+
+func init() {
+	// Assigned here because usage refers back to the list
+	commands = []command{
+		{"info", "<graph>", "print the size, degrees, connectivity and spectrum bounds of a graph", runInfo},
+		{"convert", "<graph> <output>", "write a graph in another file format", runConvert},
+		{"gft", "<graph> <signal>", "graph Fourier transform of signals", runGFT},
+		{"igft", "<graph> <coefficients>", "inverse graph Fourier transform of coefficients", runIGFT},
+		{"filter", "<graph> <signal>", "filter signals with a heat, low-pass or high-pass kernel", runFilter},
+		{"wavelet", "<graph> <signal>", "spectral graph wavelet transform of a signal", runWavelet},
+		{"plot", "<graph> [signal]", "render a graph, or a signal on it, as PNG or Graphviz DOT", runPlot},
 	}
+}
 
-	// Print Fourier Transformed signal
-	fmt.Println("\nSignal after Graph Fourier Transform:")
-	transformedSignal.PrintSignal()
+// errUsage reports wrong arguments, after the usage of the command has been printed.
+var errUsage = errors.New("usage")
 
-	// Apply Laplacian filter
-	filteredSignal, err := filters.ApplyFilter(filters.LaplacianFilter, g, signal)
-	if err != nil {
-		fmt.Println(err)
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: gogsp <command> [flags] <arguments>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
 	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "gogsp <command> -h" for the flags of a command.`)
+}
 
-	// Print signal after Laplacian filtering
-	fmt.Println("\nSignal after Laplacian filtering:")
-	filteredSignal.PrintSignal()
-
-	// Apply HighPass filter
-	filteredSignal, err = filters.ApplyFilter(filters.HighPassFilter, g, signal)
-	if err != nil {
-		fmt.Println(err)
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
 	}
-
-	// Print signal after HighPass filtering
-	fmt.Println("\nSignal after HighPass filtering:")
-	filteredSignal.PrintSignal()
-
-	// Apply LowPass filter
-	filteredSignal, err = filters.ApplyFilter(filters.LowPassFilter, g, signal)
-	if err != nil {
-		fmt.Println(err)
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
 	}
-
-	// Print signal after LowPass filtering
-	fmt.Println("\nSignal after LowPass filtering:")
-	filteredSignal.PrintSignal()
-
-	// Apply FourierFilter
-	filteredSignal, err = filters.ApplyFilter(filters.FourierFilter, g, signal)
-	if err != nil {
-		fmt.Println(err)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(os.Args[2:])
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		case err != nil:
+			fmt.Fprintln(os.Stderr, "gogsp "+name+":", err)
+			os.Exit(1)
+		}
+		return
 	}
-
-	// Print signal after Fourier filtering
-	fmt.Println("\nSignal after Fourier filtering:")
-	filteredSignal.PrintSignal()
-
+	fmt.Fprintf(os.Stderr, "gogsp: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
 }
@@ -11,7 +11,25 @@ import (
 	"github.com/wcharczuk/go-chart/drawing"
 )
 
-func PlotSignal(signal signals.Signal, name string) {
+// PlotSignal plots the signal against the matrix index of its nodes and saves the chart as name.png.
+func PlotSignal(signal signals.Signal, name string) error {
+	return plotSignal(signal, nil, name)
+}
+
+// PlotGraphSignal plots a signal on the graph, with the x axis labelled by the graph's node labels instead of
+// by matrix index.
+func PlotGraphSignal(graph *graphs.Graph, signal signals.Signal, name string) error {
+	if err := signal.CheckSize(graph); err != nil {
+		return err
+	}
+	ticks := make([]chart.Tick, 0, len(signal))
+	for i, node := range graph.Nodes() {
+		ticks = append(ticks, chart.Tick{Value: float64(i), Label: graph.Label(node)})
+	}
+	return plotSignal(signal, ticks, name)
+}
+
+func plotSignal(signal signals.Signal, ticks []chart.Tick, name string) error {
 	// Prepare the data for plotting
 	xValues := make([]float64, len(signal))
 	yOriginal := make([]float64, len(signal))
@@ -24,7 +42,8 @@ func PlotSignal(signal signals.Signal, name string) {
 	// Create a new chart
 	graphChart := chart.Chart{
 		XAxis: chart.XAxis{
-			Name: "Node",
+			Name:  "Node",
+			Ticks: ticks,
 		},
 		YAxis: chart.YAxis{
 			Name: "Signal Value",
@@ -40,34 +59,25 @@ func PlotSignal(signal signals.Signal, name string) {
 					StrokeWidth: 3,
 				},
 			},
-			&chart.ContinuousSeries{
-				Name:    "Filtered Signal",
-				XValues: xValues,
-				Style: chart.Style{
-					Show:        true,
-					StrokeColor: drawing.ColorRed,
-					StrokeWidth: 3,
-				},
-			},
 		},
 	}
 
-	// Save the chart to a file
-	file_name := fmt.Sprintf("%s.png", name)
-	file, err := os.Create(file_name)
-	if err != nil {
-		fmt.Println("Error creating file:", err)
-		return
-	}
-	defer file.Close()
+	return save(graphChart, name)
+}
 
-	err = graphChart.Render(chart.PNG, file)
+// save renders the chart to name.png. A file that fails to be rendered is removed.
+func save(graphChart chart.Chart, name string) error {
+	fileName := fmt.Sprintf("%s.png", name)
+	file, err := os.Create(fileName)
 	if err != nil {
-		fmt.Println("Error rendering chart:", err)
-		return
+		return fmt.Errorf("creating the chart file: %w", err)
 	}
-
-	fmt.Println("Chart saved to graph_signal.png")
+	if err := graphChart.Render(chart.PNG, file); err != nil {
+		file.Close()
+		os.Remove(fileName)
+		return fmt.Errorf("rendering the chart: %w", err)
+	}
+	return file.Close()
 }
 
 func applyFunctionToFloat64Array(arr []float64, fn func(float64) float64) []float64 {
@@ -88,61 +98,73 @@ func Abs(x float64) float64 {
 	return x
 }
 
-func PlotGraph(graph *graphs.Graph, name string) {
-	// Prepare the data for plotting
-	nodes := make([]float64, 0)
-	edgesX := make([]float64, 0)
-	edgesY := make([]float64, 0)
-
-	for node, edges := range graph.AdjacencyList {
-		nodes = append(nodes, float64(node))
-		for _, edge := range edges {
-			edgesX = append(edgesX, float64(node))
-			edgesX = append(edgesX, float64(edge.Node))
-			edgesY = append(edgesY, float64(node))
-			edgesY = append(edgesY, float64(edge.Node))
+// PlotGraph draws the graph with its nodes at their coordinates, coloured by height, and its edges as segments.
+// Only the first two coordinates are used. Graphs whose nodes lack coordinates are drawn on a circle, in matrix
+// order.
+func PlotGraph(graph *graphs.Graph, name string) error {
+	xs, ys := positions(graph)
+
+	// One two-point series per edge, drawn below the nodes
+	series := make([]chart.Series, 0)
+	for i, node := range graph.Nodes() {
+		for _, edge := range graph.AdjacencyList[node] {
+			j, _ := graph.NodeIndex(edge.Node)
+			if !graph.IsDirected() && j < i {
+				continue
+			}
+			series = append(series, &chart.ContinuousSeries{
+				Style: chart.Style{
+					Show:        true,
+					StrokeColor: drawing.ColorFromHex("b0b0b0"),
+					StrokeWidth: 1,
+				},
+				XValues: []float64{xs[i], xs[j]},
+				YValues: []float64{ys[i], ys[j]},
+			})
 		}
 	}
-	fmt.Print(nodes, "\n")
 
 	viridisByY := func(xr, yr chart.Range, index int, x, y float64) drawing.Color {
 		return chart.Viridis(y, yr.GetMin(), yr.GetMax())
 	}
-
-	// Duplicate the first node at the end to form a ring/oval shape
-	nodes = []float64{(1 / math.Sqrt(2)), (1 / math.Sqrt(2)), (-1 / math.Sqrt(2)), (-1 / math.Sqrt(2))}
-	resultArray := []float64{(1 / math.Sqrt(2)), (-1 / math.Sqrt(2)), (-1 / math.Sqrt(2)), (1 / math.Sqrt(2))}
+	series = append(series, &chart.ContinuousSeries{
+		Style: chart.Style{
+			Show:             true,
+			StrokeWidth:      chart.Disabled,
+			DotWidth:         5,
+			DotColorProvider: viridisByY,
+		},
+		XValues: xs,
+		YValues: ys,
+	})
 
 	// Create a new chart
 	graphChart := chart.Chart{
-		Series: []chart.Series{
-			&chart.ContinuousSeries{
-				Style: chart.Style{
-					Show:             true,
-					StrokeWidth:      chart.Disabled,
-					DotWidth:         5,
-					DotColorProvider: viridisByY,
-				},
-				XValues: nodes,
-				YValues: resultArray,
-			},
-		},
+		Series: series,
 	}
 
-	// Save the chart to a file
-	fileName := fmt.Sprintf("%s.png", name)
-	file, err := os.Create(fileName)
-	if err != nil {
-		fmt.Println("Error creating file:", err)
-		return
-	}
-	defer file.Close()
+	return save(graphChart, name)
+}
 
-	err = graphChart.Render(chart.PNG, file)
-	if err != nil {
-		fmt.Println("Error rendering chart:", err)
-		return
+// positions returns the plane coordinates of the nodes in matrix order: their first two coordinates when every node
+// has some, and evenly spaced points on the unit circle otherwise.
+func positions(graph *graphs.Graph) ([]float64, []float64) {
+	nodes := graph.Nodes()
+	xs := make([]float64, len(nodes))
+	ys := make([]float64, len(nodes))
+	if graph.HasCoordinates() {
+		for i, node := range nodes {
+			c, _ := graph.Coordinates(node)
+			xs[i] = c[0]
+			if len(c) > 1 {
+				ys[i] = c[1]
+			}
+		}
+		return xs, ys
 	}
-
-	fmt.Println("Chart saved to", fileName)
+	for i := range nodes {
+		angle := 2 * math.Pi * float64(i) / float64(len(nodes))
+		xs[i], ys[i] = math.Cos(angle), math.Sin(angle)
+	}
+	return xs, ys
 }
@@ -0,0 +1,121 @@
+// csv.go contains the CSV format of signals: a header row with the node labels, then one row per signal with a
+// value per node, which is how a table of time series with a column per sensor is usually saved. The columns may be
+// in any order.
+
+package signals
+
+import (
+	"encoding/csv"
+	"errors"
+	"example/gogsp/core"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadCSV reads the signals of a CSV file as an N x T matrix, the signal of each row becoming a column.
+func ReadCSV(r io.Reader, g *core.Graph) (*mat.Dense, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("empty signal file")
+	}
+	if err != nil {
+		return nil, csvError(err, "the header of node labels", nil)
+	}
+	for k := range header {
+		header[k] = strings.TrimSpace(header[k])
+	}
+	rows, err := nodeRows(g, header)
+	if err != nil {
+		return nil, fmt.Errorf("line 1: %w", err)
+	}
+
+	var samples [][]float64
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// The reader checks that every record has as many fields as the header
+			return nil, csvError(err, "a signal", header)
+		}
+		line, _ := reader.FieldPos(0)
+		sample := make([]float64, len(fields))
+		for k, field := range fields {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid value %q for node %q", line, field, header[k])
+			}
+			sample[rows[k]] = v
+		}
+		samples = append(samples, sample)
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("signal file has a header but no values")
+	}
+
+	m := mat.NewDense(len(rows), len(samples), nil)
+	for t, sample := range samples {
+		m.SetCol(t, sample)
+	}
+	return m, nil
+}
+
+// csvError adds the line and what was being read to an error of the CSV reader.
+func csvError(err error, what string, header []string) error {
+	var parseError *csv.ParseError
+	if !errors.As(err, &parseError) {
+		return fmt.Errorf("reading %s: %w", what, err)
+	}
+	if errors.Is(err, csv.ErrFieldCount) {
+		return fmt.Errorf("line %d: %s needs %d values, one per node of the header: %w", parseError.Line, what, len(header), parseError.Err)
+	}
+	return fmt.Errorf("line %d: reading %s: %w", parseError.Line, what, parseError.Err)
+}
+
+// ReadSignalCSV reads a CSV file holding a single signal.
+func ReadSignalCSV(r io.Reader, g *core.Graph) (Signal, error) {
+	m, err := ReadCSV(r, g)
+	if err != nil {
+		return nil, err
+	}
+	return column(m)
+}
+
+// WriteCSV writes the columns of an N x T matrix as T rows under a header of node labels in matrix order.
+func WriteCSV(w io.Writer, g *core.Graph, m mat.Matrix) error {
+	if err := checkRows(g, m); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(labels(g)); err != nil {
+		return err
+	}
+	r, c := m.Dims()
+	row := make([]string, r)
+	for t := 0; t < c; t++ {
+		for i := range row {
+			row[i] = strconv.FormatFloat(m.At(i, t), 'g', -1, 64)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSignalCSV writes a single signal.
+func WriteSignalCSV(w io.Writer, g *core.Graph, s Signal) error {
+	if err := s.CheckSize(g); err != nil {
+		return err
+	}
+	return WriteCSV(w, g, AsMatrix(s))
+}
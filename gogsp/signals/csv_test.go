@@ -0,0 +1,44 @@
+package signals
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	g := triangle()
+	m := mat.NewDense(3, 2, []float64{1, -2, 3.5, 4, 0, 0.1})
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, g, m); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadCSV(&buf, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(m, back) {
+		t.Errorf("got %v, want %v", mat.Formatted(back), mat.Formatted(m))
+	}
+}
+
+func TestCSVErrors(t *testing.T) {
+	g := triangle()
+	tests := []struct {
+		file, want string
+	}{
+		{"2,0,1\n1,2,3\n4,5\n", "line 3: a signal needs 3 values, one per node of the header"},
+		{"0,1,2\n1,\"2,3\n", "line 2: reading a signal"},
+		{"0,\"1,2\n", "line 1: reading the header of node labels"},
+		{"0,1,2\n1,x,3\n", `line 2: invalid value "x" for node "1"`},
+		{"0,1,7\n1,2,3\n", `line 1: node "7" is not in the graph`},
+	}
+	for _, test := range tests {
+		_, err := ReadCSV(strings.NewReader(test.file), g)
+		if err == nil || !strings.HasPrefix(err.Error(), test.want) {
+			t.Errorf("%q: got %v, want %s", test.file, err, test.want)
+		}
+	}
+}
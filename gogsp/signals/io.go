@@ -0,0 +1,74 @@
+// io.go contains what the signal readers and writers share. Files hold either a single signal or a matrix of T
+// signals, one column per signal and one row per node, as used by the filter banks. Rows are in the matrix order of
+// the graph; formats that name the nodes, like CSV and JSON, name them by label, see Graph.Label, and the readers
+// reorder them to the matrix order, rejecting unknown, repeated and missing nodes.
+
+package signals
+
+import (
+	"example/gogsp/core"
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// nodeRows returns the matrix index of each named node of the graph, and checks that every node is named once.
+func nodeRows(g *core.Graph, names []string) ([]int, error) {
+	rows := make([]int, len(names))
+	seen := make(map[int]string, len(names))
+	for k, name := range names {
+		n, ok := g.NodeByLabel(name)
+		if !ok {
+			return nil, fmt.Errorf("node %q is not in the graph", name)
+		}
+		i, _ := g.NodeIndex(n)
+		if first, dup := seen[i]; dup {
+			return nil, fmt.Errorf("node %q is given twice, first as %q", name, first)
+		}
+		seen[i] = name
+		rows[k] = i
+	}
+	if nodes := g.Nodes(); len(seen) != len(nodes) {
+		for i, n := range nodes {
+			if _, ok := seen[i]; !ok {
+				return nil, fmt.Errorf("node %q of the graph has no value, %d of %d nodes are given", g.Label(n), len(seen), len(nodes))
+			}
+		}
+	}
+	return rows, nil
+}
+
+// labels returns the labels of the nodes of the graph in matrix order.
+func labels(g *core.Graph) []string {
+	nodes := g.Nodes()
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = g.Label(n)
+	}
+	return names
+}
+
+// checkRows checks that a matrix has a row per node of the graph.
+func checkRows(g *core.Graph, m mat.Matrix) error {
+	r, c := m.Dims()
+	if n := len(g.Nodes()); r != n {
+		return &SizeMismatchError{What: "matrix", Got: r, Of: "graph", Want: n}
+	}
+	if c == 0 {
+		return fmt.Errorf("matrix has no signals")
+	}
+	return nil
+}
+
+// column returns the only column of a matrix read for a single signal.
+func column(m *mat.Dense) (Signal, error) {
+	if _, c := m.Dims(); c != 1 {
+		return nil, fmt.Errorf("expected a single signal, got %d", c)
+	}
+	return Signal(mat.Col(nil, 0, m)), nil
+}
+
+// AsMatrix returns a copy of the signal as an N x 1 matrix, e.g. to write it with the signals of a matrix file.
+func AsMatrix(s Signal) *mat.Dense {
+	return mat.NewDense(len(s), 1, append([]float64(nil), s...))
+}
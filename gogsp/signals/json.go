@@ -0,0 +1,137 @@
+// json.go contains the JSON format of signals: an object from node labels to values, a number per node for a
+// single signal, e.g. {"0": 1.5, "1": -2}, and an array of T numbers per node for a matrix of signals. This is what
+// a pandas Series or a dict of lists gives with json.dump.
+
+package signals
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"example/gogsp/core"
+	"fmt"
+	"io"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadJSON reads the signals of a JSON object as an N x T matrix. Single signals give a matrix with one column.
+func ReadJSON(r io.Reader, g *core.Graph) (*mat.Dense, error) {
+	// The object is decoded token by token to keep its keys in file order for the error messages
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, errors.New("expected a JSON object from node labels to values")
+	}
+	var names []string
+	var values [][]float64
+	scalar := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		name := token.(string)
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		raw = bytes.TrimSpace(raw)
+		var sample []float64
+		if len(raw) > 0 && raw[0] == '[' {
+			if err := json.Unmarshal(raw, &sample); err != nil {
+				return nil, fmt.Errorf("node %q: expected an array of numbers", name)
+			}
+		} else {
+			var v float64
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("node %q: expected a number or an array of numbers", name)
+			}
+			sample = []float64{v}
+		}
+		if len(names) == 0 {
+			scalar = raw[0] != '['
+		} else if scalar != (raw[0] != '[') {
+			return nil, fmt.Errorf("node %q: numbers and arrays are mixed", name)
+		} else if len(sample) != len(values[0]) {
+			return nil, fmt.Errorf("node %q has %d values but node %q has %d", name, len(sample), names[0], len(values[0]))
+		}
+		names = append(names, name)
+		values = append(values, sample)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return nil, errors.New("the JSON object has no nodes")
+	}
+	rows, err := nodeRows(g, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(values[0]) == 0 {
+		return nil, errors.New("the signal arrays are empty")
+	}
+	m := mat.NewDense(len(rows), len(values[0]), nil)
+	for k, sample := range values {
+		m.SetRow(rows[k], sample)
+	}
+	return m, nil
+}
+
+// ReadSignalJSON reads a JSON object holding a single signal.
+func ReadSignalJSON(r io.Reader, g *core.Graph) (Signal, error) {
+	m, err := ReadJSON(r, g)
+	if err != nil {
+		return nil, err
+	}
+	return column(m)
+}
+
+// WriteJSON writes an N x T matrix as an object from node labels, in matrix order, to arrays of T values.
+// JSON has no NaN or infinities, so the values must be finite.
+func WriteJSON(w io.Writer, g *core.Graph, m mat.Matrix) error {
+	if err := checkRows(g, m); err != nil {
+		return err
+	}
+	_, c := m.Dims()
+	return writeJSON(w, g, func(i int) interface{} {
+		row := make([]float64, c)
+		for t := range row {
+			row[t] = m.At(i, t)
+		}
+		return row
+	})
+}
+
+// WriteSignalJSON writes a single signal as an object from node labels to numbers.
+func WriteSignalJSON(w io.Writer, g *core.Graph, s Signal) error {
+	if err := s.CheckSize(g); err != nil {
+		return err
+	}
+	return writeJSON(w, g, func(i int) interface{} {
+		return s[i]
+	})
+}
+
+// writeJSON writes the object with the value of each node, one node per line.
+func writeJSON(w io.Writer, g *core.Graph, value func(i int) interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, name := range labels(g) {
+		key, _ := json.Marshal(name)
+		v, err := json.Marshal(value(i))
+		if err != nil {
+			return fmt.Errorf("node %q: %w", name, err)
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, "\n  %s: %s", key, v)
+	}
+	buf.WriteString("\n}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
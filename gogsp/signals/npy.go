@@ -0,0 +1,436 @@
+// npy.go contains the NumPy formats of signals. A .npy file holds one array, of shape (N,) for a single signal or
+// (N, T) for a matrix of signals; it has no node names, so its rows must be in the matrix order of the graph.
+// A .npz file is a zip of named .npy arrays, and may hold a "nodes" array with the node labels, or integer IDs, of
+// the rows, in which case the readers reorder the rows to the matrix order like the other formats.
+// Arrays of any integer or float type, in either byte order and C or Fortran order, are read as float64; arrays are
+// written as little-endian float64 in C order, which np.load reads directly.
+
+package signals
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"example/gogsp/core"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const npyMagic = "\x93NUMPY"
+
+// npzNodes is the name of the array holding the node names in a .npz file.
+const npzNodes = "nodes"
+
+// npyMaxHeader is the longest .npy header read, the limit np.load applies as well. Headers of numeric arrays are
+// much shorter; the limit keeps a corrupt length from allocating gigabytes.
+const npyMaxHeader = 10000
+
+// npyChunk is the number of bytes of data decoded at a time.
+const npyChunk = 1 << 16
+
+// npyArray is a decoded .npy array, with its values in C order as numbers or, for arrays of strings, as text.
+type npyArray struct {
+	shape  []int
+	values []float64
+	text   []string
+}
+
+var (
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+// readNPY decodes a .npy array of shape (N,) or (N, T) for a graph of n nodes. The shape is checked before any
+// data is read, and the data is decoded a chunk at a time, so that a file claiming more values than it holds fails
+// without allocating for them.
+func readNPY(r io.Reader, n int) (*npyArray, error) {
+	var preamble [8]byte
+	if _, err := io.ReadFull(r, preamble[:]); err != nil {
+		return nil, fmt.Errorf("not a .npy file: %w", err)
+	}
+	if string(preamble[:6]) != npyMagic {
+		return nil, errors.New("not a .npy file")
+	}
+	var length uint32
+	switch preamble[6] {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		length = uint32(n)
+	case 2, 3:
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported .npy version %d.%d", preamble[6], preamble[7])
+	}
+	if length > npyMaxHeader {
+		return nil, fmt.Errorf("the .npy header is %d bytes long, more than the %d allowed", length, npyMaxHeader)
+	}
+	header := make([]byte, length)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	descr := npyDescr.FindSubmatch(header)
+	fortran := npyFortran.FindSubmatch(header)
+	shapeText := npyShape.FindSubmatch(header)
+	if descr == nil || fortran == nil || shapeText == nil {
+		return nil, fmt.Errorf("invalid .npy header %q", strings.TrimSpace(string(header)))
+	}
+	array := &npyArray{}
+	for _, dim := range strings.Split(string(shapeText[1]), ",") {
+		if dim = strings.TrimSpace(dim); dim == "" {
+			continue
+		}
+		d, err := strconv.Atoi(dim)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid .npy shape (%s)", shapeText[1])
+		}
+		array.shape = append(array.shape, d)
+	}
+	if len(array.shape) != 1 && len(array.shape) != 2 {
+		return nil, fmt.Errorf("expected an array of shape (N,) or (N, T), got (%s)", shapeText[1])
+	}
+	if array.shape[0] != n {
+		return nil, &SizeMismatchError{What: "array", Got: array.shape[0], Of: "graph", Want: n}
+	}
+
+	dtype := string(descr[1])
+	if len(dtype) < 3 {
+		return nil, fmt.Errorf("unsupported .npy type %q", dtype)
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if dtype[0] == '>' {
+		order = binary.BigEndian
+	}
+	kind := dtype[1]
+	width, err := strconv.Atoi(dtype[2:])
+	if err != nil {
+		return nil, fmt.Errorf("unsupported .npy type %q", dtype)
+	}
+	if kind == 'U' {
+		// Fixed-width UTF-32 strings
+		if width > npyChunk/4 {
+			return nil, fmt.Errorf("unsupported .npy type %q, the strings are too long", dtype)
+		}
+		width *= 4
+	}
+	if width < 1 || width > npyChunk {
+		return nil, fmt.Errorf("unsupported .npy type %q", dtype)
+	}
+	if kind != 'U' {
+		if _, err := npyValue(kind, width, make([]byte, width), order); err != nil {
+			return nil, fmt.Errorf("unsupported .npy type %q", dtype)
+		}
+	}
+	size := 1
+	for _, d := range array.shape {
+		if d > 0 && size > math.MaxInt/d {
+			return nil, fmt.Errorf("the .npy shape (%s) is too large", shapeText[1])
+		}
+		size *= d
+	}
+	if size > math.MaxInt/width {
+		return nil, fmt.Errorf("the .npy shape (%s) is too large", shapeText[1])
+	}
+
+	data := make([]byte, npyChunk/width*width)
+	for k := 0; k < size; {
+		count := len(data) / width
+		if rest := size - k; rest < count {
+			count = rest
+		}
+		if _, err := io.ReadFull(r, data[:count*width]); err != nil {
+			return nil, fmt.Errorf("truncated .npy data, %d of %d values: %w", k, size, err)
+		}
+		for c := 0; c < count; c++ {
+			item := data[c*width : (c+1)*width]
+			if kind == 'U' {
+				array.text = append(array.text, decodeUTF32(item, order))
+				continue
+			}
+			v, _ := npyValue(kind, width, item, order)
+			array.values = append(array.values, v)
+		}
+		k += count
+	}
+
+	if string(fortran[1]) == "True" && len(array.shape) == 2 {
+		rows, cols := array.shape[0], array.shape[1]
+		values := make([]float64, len(array.values))
+		for i := 0; i < rows; i++ {
+			for j := 0; j < cols; j++ {
+				values[i*cols+j] = array.values[j*rows+i]
+			}
+		}
+		array.values = values
+	}
+	return array, nil
+}
+
+// npyValue decodes a number of the given kind and width.
+func npyValue(kind byte, width int, item []byte, order binary.ByteOrder) (float64, error) {
+	switch {
+	case kind == 'f' && width == 8:
+		return math.Float64frombits(order.Uint64(item)), nil
+	case kind == 'f' && width == 4:
+		return float64(math.Float32frombits(order.Uint32(item))), nil
+	case (kind == 'i' || kind == 'u' || kind == 'b') && width == 1:
+		if kind == 'i' {
+			return float64(int8(item[0])), nil
+		}
+		return float64(item[0]), nil
+	case kind == 'i' && width == 2:
+		return float64(int16(order.Uint16(item))), nil
+	case kind == 'u' && width == 2:
+		return float64(order.Uint16(item)), nil
+	case kind == 'i' && width == 4:
+		return float64(int32(order.Uint32(item))), nil
+	case kind == 'u' && width == 4:
+		return float64(order.Uint32(item)), nil
+	case kind == 'i' && width == 8:
+		return float64(int64(order.Uint64(item))), nil
+	case kind == 'u' && width == 8:
+		return float64(order.Uint64(item)), nil
+	}
+	return 0, errors.New("unsupported type")
+}
+
+func decodeUTF32(item []byte, order binary.ByteOrder) string {
+	var sb strings.Builder
+	for k := 0; k+4 <= len(item); k += 4 {
+		r := rune(order.Uint32(item[k:]))
+		if r == 0 {
+			break
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// writeNPY encodes an array of version 1.0 with the given type and shape.
+func writeNPY(w io.Writer, dtype string, shape []int, data []byte) error {
+	dims := make([]string, len(shape))
+	for k, d := range shape {
+		dims[k] = strconv.Itoa(d)
+	}
+	shapeText := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeText += ","
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", dtype, shapeText)
+	// The preamble and header are padded with spaces to a multiple of 64 bytes, ending with a newline
+	padding := 64 - (len(npyMagic)+4+len(header)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+	header += strings.Repeat(" ", padding) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	buf.Write(data)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeFloats writes float64 values in C order.
+func writeFloats(w io.Writer, shape []int, values []float64) error {
+	data := make([]byte, 8*len(values))
+	for k, v := range values {
+		binary.LittleEndian.PutUint64(data[8*k:], math.Float64bits(v))
+	}
+	return writeNPY(w, "<f8", shape, data)
+}
+
+// writeStrings writes strings as fixed-width UTF-32.
+func writeStrings(w io.Writer, text []string) error {
+	width := 1
+	for _, s := range text {
+		if n := utf8.RuneCountInString(s); n > width {
+			width = n
+		}
+	}
+	data := make([]byte, 4*width*len(text))
+	for k, s := range text {
+		offset := 4 * width * k
+		for _, r := range s {
+			binary.LittleEndian.PutUint32(data[offset:], uint32(r))
+			offset += 4
+		}
+	}
+	return writeNPY(w, "<U"+strconv.Itoa(width), []int{len(text)}, data)
+}
+
+// matrix returns a numeric array of shape (N,) or (N, T) as an N x T matrix.
+func (a *npyArray) matrix() (*mat.Dense, error) {
+	if a.text != nil {
+		return nil, errors.New("expected a numeric array, got strings")
+	}
+	switch len(a.shape) {
+	case 1:
+		if a.shape[0] == 0 {
+			return nil, errors.New("the array is empty")
+		}
+		return mat.NewDense(a.shape[0], 1, a.values), nil
+	case 2:
+		if a.shape[0] == 0 || a.shape[1] == 0 {
+			return nil, fmt.Errorf("the array of shape (%d, %d) is empty", a.shape[0], a.shape[1])
+		}
+		return mat.NewDense(a.shape[0], a.shape[1], a.values), nil
+	}
+	return nil, fmt.Errorf("expected an array of shape (N,) or (N, T), got %d dimensions", len(a.shape))
+}
+
+// ReadNPY reads a .npy array of shape (N,) or (N, T) as an N x T matrix, its rows in matrix order.
+func ReadNPY(r io.Reader, g *core.Graph) (*mat.Dense, error) {
+	array, err := readNPY(r, len(g.Nodes()))
+	if err != nil {
+		return nil, err
+	}
+	m, err := array.matrix()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(g, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReadSignalNPY reads a .npy array holding a single signal, of shape (N,) or (N, 1).
+func ReadSignalNPY(r io.Reader, g *core.Graph) (Signal, error) {
+	m, err := ReadNPY(r, g)
+	if err != nil {
+		return nil, err
+	}
+	return column(m)
+}
+
+// WriteNPY writes an N x T matrix as an array of shape (N, T).
+func WriteNPY(w io.Writer, g *core.Graph, m mat.Matrix) error {
+	if err := checkRows(g, m); err != nil {
+		return err
+	}
+	r, c := m.Dims()
+	return writeFloats(w, []int{r, c}, mat.DenseCopyOf(m).RawMatrix().Data)
+}
+
+// WriteSignalNPY writes a single signal as an array of shape (N,).
+func WriteSignalNPY(w io.Writer, g *core.Graph, s Signal) error {
+	if err := s.CheckSize(g); err != nil {
+		return err
+	}
+	return writeFloats(w, []int{len(s)}, s)
+}
+
+// ReadNPZ reads the arrays of a .npz file, e.g. as opened by os.Open and Stat, by name without the .npy extension.
+// Each array becomes an N x T matrix, with its rows reordered to the matrix order if the file has a "nodes" array.
+func ReadNPZ(r io.ReaderAt, size int64, g *core.Graph) (map[string]*mat.Dense, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	arrays := make(map[string]*npyArray)
+	for _, file := range archive.File {
+		name := strings.TrimSuffix(file.Name, ".npy")
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		array, err := readNPY(rc, len(g.Nodes()))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		arrays[name] = array
+	}
+
+	var rows []int
+	if nodes, ok := arrays[npzNodes]; ok {
+		delete(arrays, npzNodes)
+		names := nodes.text
+		if names == nil {
+			// Integer node IDs
+			for _, v := range nodes.values {
+				names = append(names, strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		}
+		if rows, err = nodeRows(g, names); err != nil {
+			return nil, fmt.Errorf("%s: %w", npzNodes, err)
+		}
+	}
+
+	matrices := make(map[string]*mat.Dense, len(arrays))
+	for name, array := range arrays {
+		m, err := array.matrix()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := checkRows(g, m); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if rows != nil {
+			r, c := m.Dims()
+			ordered := mat.NewDense(r, c, nil)
+			for k, i := range rows {
+				ordered.SetRow(i, m.RawRowView(k))
+			}
+			m = ordered
+		}
+		matrices[name] = m
+	}
+	return matrices, nil
+}
+
+// WriteNPZ writes N x T matrices as named arrays of shape (N, T), together with a "nodes" array of the node labels
+// in matrix order. Signals can be written as well with AsMatrix.
+func WriteNPZ(w io.Writer, g *core.Graph, matrices map[string]mat.Matrix) error {
+	names := make([]string, 0, len(matrices))
+	for name, m := range matrices {
+		if name == npzNodes {
+			return fmt.Errorf("the array name %q is reserved for the node labels", npzNodes)
+		}
+		if err := checkRows(g, m); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	entry, err := archive.Create(npzNodes + ".npy")
+	if err != nil {
+		return err
+	}
+	if err := writeStrings(entry, labels(g)); err != nil {
+		return err
+	}
+	for _, name := range names {
+		entry, err := archive.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		m := matrices[name]
+		r, c := m.Dims()
+		if err := writeFloats(entry, []int{r, c}, mat.DenseCopyOf(m).RawMatrix().Data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,126 @@
+package signals
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"example/gogsp/core"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// triangle returns a graph of 3 nodes.
+func triangle() *core.Graph {
+	g := core.NewGraph()
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 0, 1)
+	return g
+}
+
+// npyFile returns a .npy file of version 1.0 with the given header and data.
+func npyFile(header string, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestNPYRoundTrip(t *testing.T) {
+	g := triangle()
+	m := mat.NewDense(3, 2, []float64{1, -2, 3.5, 4, 0, 1e-300})
+	var buf bytes.Buffer
+	if err := WriteNPY(&buf, g, m); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadNPY(&buf, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(m, back) {
+		t.Errorf("got %v, want %v", mat.Formatted(back), mat.Formatted(m))
+	}
+}
+
+func TestNPYRejectsMalformedFiles(t *testing.T) {
+	g := triangle()
+	var sizeError *SizeMismatchError
+	tests := []struct {
+		name  string
+		file  []byte
+		check func(error) bool
+	}{
+		{
+			name:  "more rows than nodes",
+			file:  npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (4,), }\n", make([]byte, 32)),
+			check: func(err error) bool { return errors.As(err, &sizeError) },
+		},
+		{
+			name: "a shape whose size overflows",
+			file: npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (3, 4611686018427387904), }\n", nil),
+			check: func(err error) bool {
+				return strings.Contains(err.Error(), "too large")
+			},
+		},
+		{
+			name: "a shape much larger than the data",
+			file: npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (3, 100000000000), }\n", make([]byte, 64)),
+			check: func(err error) bool {
+				return strings.Contains(err.Error(), "truncated .npy data")
+			},
+		},
+		{
+			name: "three dimensions",
+			file: npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (3, 1, 1), }\n", make([]byte, 24)),
+			check: func(err error) bool {
+				return strings.Contains(err.Error(), "expected an array of shape")
+			},
+		},
+		{
+			name: "a huge header",
+			file: append([]byte(npyMagic+"\x02\x00"), 0xff, 0xff, 0xff, 0xff),
+			check: func(err error) bool {
+				return strings.Contains(err.Error(), "header is")
+			},
+		},
+		{
+			name: "an unsupported type",
+			file: npyFile("{'descr': '<c16', 'fortran_order': False, 'shape': (3,), }\n", make([]byte, 48)),
+			check: func(err error) bool {
+				return strings.Contains(err.Error(), "unsupported .npy type")
+			},
+		},
+	}
+	for _, test := range tests {
+		_, err := ReadNPY(bytes.NewReader(test.file), g)
+		if err == nil || !test.check(err) {
+			t.Errorf("%s: got %v", test.name, err)
+		}
+	}
+}
+
+func TestNPZRoundTrip(t *testing.T) {
+	g := core.NewGraph()
+	g.AddEdge(5, 2, 1)
+	g.AddEdge(2, 9, 1)
+	if err := g.SetLabel(9, "nine"); err != nil {
+		t.Fatal(err)
+	}
+	m := mat.NewDense(3, 1, []float64{1, 2, 3})
+	var buf bytes.Buffer
+	if err := WriteNPZ(&buf, g, map[string]mat.Matrix{"signal": m}); err != nil {
+		t.Fatal(err)
+	}
+	arrays, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()), g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back, ok := arrays["signal"]; !ok || !mat.Equal(m, back) {
+		t.Errorf("got %v, want the signal %v", arrays, mat.Formatted(m))
+	}
+}